@@ -7,12 +7,15 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/achyar10/go-zteolt/internal/api"
 	"github.com/achyar10/go-zteolt/internal/config"
 	"github.com/achyar10/go-zteolt/internal/olt"
+	"github.com/achyar10/go-zteolt/internal/utils"
+	"github.com/achyar10/go-zteolt/templates"
 )
 
 func main() {
@@ -21,6 +24,17 @@ func main() {
 		host = flag.String("host", "0.0.0.0", "Server host")
 		port = flag.Int("port", 8080, "Server port")
 		dev  = flag.Bool("dev", false, "Development mode")
+
+		templatesDir   = flag.String("templates", "templates", "Template directory (embedded templates are used as fallback)")
+		templateReload = flag.Duration("template-reload", 5*time.Second, "Template directory poll interval (0 disables hot reload)")
+		devicesFile    = flag.String("devices", "", "JSON device inventory mapping OLT hosts to model/firmware")
+		thresholdsFile = flag.String("thresholds", "", "JSON optical threshold profiles assigned per OLT and PON")
+		onuModelsFile  = flag.String("onu-models", "", "JSON ONU model catalog merged into the built-in catalog")
+		oidProfileFile = flag.String("oid-profiles", "", "JSON SNMP OID profiles merged into the built-in profiles")
+		trapsFile      = flag.String("traps", "", "JSON SNMP trap receiver settings (listener, credentials, sinks)")
+
+		bulkAdd        = flag.String("bulk-add", "", "Provision ONUs from a CSV/JSON file and exit")
+		bulkRenderOnly = flag.Bool("bulk-render-only", false, "Only render commands for -bulk-add")
 	)
 	flag.Parse()
 
@@ -28,23 +42,110 @@ func main() {
 	cfg := config.DefaultConfig()
 	cfg.Server.Host = *host
 	cfg.Server.Port = *port
+	cfg.Templates.Dir = *templatesDir
+	cfg.Templates.ReloadInterval = *templateReload
+	cfg.Devices.File = *devicesFile
+	cfg.Thresholds.File = *thresholdsFile
+	cfg.Traps.File = *trapsFile
 
 	// Initialize services
 	log.Println("🚀 Initializing ZTE OLT Management API...")
 
 	// Initialize template manager
-	templateMgr, err := config.NewTemplateManager()
+	templateMgr, err := config.NewTemplateManager(cfg.Templates.Dir, templates.FS)
 	if err != nil {
 		log.Fatalf("❌ Failed to initialize template manager: %v", err)
 	}
+	defer templateMgr.Close()
 	log.Printf("✅ Loaded %d templates", len(templateMgr.GetAvailableTemplates()))
 
+	// Initialize service profiles
+	profiles, err := config.NewProfileRegistry(cfg.Templates.Dir, templates.FS)
+	if err != nil {
+		log.Fatalf("❌ Failed to load service profiles: %v", err)
+	}
+	if err := profiles.Validate(templateMgr); err != nil {
+		log.Fatalf("❌ Invalid service profiles: %v", err)
+	}
+	log.Printf("✅ Loaded %d service profiles", len(profiles.List()))
+
+	// Extend the SNMP OID profiles
+	if *oidProfileFile != "" {
+		if err := olt.LoadOIDProfiles(*oidProfileFile); err != nil {
+			log.Fatalf("❌ Failed to load OID profiles: %v", err)
+		}
+		log.Printf("✅ Loaded OID profiles from %s (%d profiles)", *oidProfileFile, len(olt.OIDProfiles()))
+	}
+
+	// Initialize device inventory
+	devices, err := config.NewDeviceRegistry(cfg.Devices.File)
+	if err != nil {
+		log.Fatalf("❌ Failed to load devices: %v", err)
+	}
+	for _, d := range devices.List() {
+		if _, err := olt.GetDriver(d.Vendor); err != nil {
+			log.Fatalf("❌ Device %s: %v", d.Host, err)
+		}
+		if _, err := olt.SelectOIDProfile(d.Vendor, d.Model, d.Firmware, d.OIDProfile); err != nil {
+			log.Fatalf("❌ Device %s: %v", d.Host, err)
+		}
+	}
+	if cfg.Devices.File != "" {
+		log.Printf("✅ Loaded %d devices from %s", len(devices.List()), cfg.Devices.File)
+	}
+
+	// Initialize optical threshold profiles
+	thresholds, err := config.NewThresholdRegistry(cfg.Thresholds.File)
+	if err != nil {
+		log.Fatalf("❌ Failed to load optical thresholds: %v", err)
+	}
+	if cfg.Thresholds.File != "" {
+		log.Printf("✅ Loaded %d optical threshold profiles from %s", len(thresholds.Profiles()), cfg.Thresholds.File)
+	}
+
+	// Extend the ONU model catalog
+	if *onuModelsFile != "" {
+		if err := utils.LoadONUModelCatalog(*onuModelsFile); err != nil {
+			log.Fatalf("❌ Failed to load ONU model catalog: %v", err)
+		}
+		log.Printf("✅ Loaded ONU model catalog from %s (%d models)", *onuModelsFile, len(utils.ONUModels()))
+	}
+
 	// Initialize OLT service
 	oltService := olt.NewService(cfg.OLT.DefaultTimeout)
 	log.Printf("✅ OLT service initialized with timeout: %v", cfg.OLT.DefaultTimeout)
 
+	// Initialize SNMP trap receiver
+	var traps *olt.TrapReceiver
+	var trapListen string
+	if cfg.Traps.File != "" {
+		traps, trapListen, err = newTrapReceiver(cfg.Traps.File, devices)
+		if err != nil {
+			log.Fatalf("❌ Failed to initialize SNMP trap receiver: %v", err)
+		}
+	}
+
 	// Initialize API handlers
-	handlers := api.NewHandlers(oltService, templateMgr)
+	handlers := api.NewHandlers(cfg, oltService, templateMgr, profiles, devices, thresholds, traps)
+
+	// Run bulk provisioning from file instead of starting the server
+	if *bulkAdd != "" {
+		os.Exit(runBulkAdd(handlers, *bulkAdd, *bulkRenderOnly))
+	}
+
+	// Watch template directory for changes
+	templateMgr.Watch(cfg.Templates.ReloadInterval)
+
+	// Start SNMP trap receiver
+	if traps != nil {
+		go func() {
+			if err := traps.Listen(trapListen); err != nil {
+				log.Fatalf("❌ SNMP trap receiver failed to start: %v", err)
+			}
+		}()
+		defer traps.Close()
+		log.Printf("📟 SNMP trap receiver listening on %s", trapListen)
+	}
 
 	// Setup Fiber routes
 	app := api.SetupRoutes(handlers)
@@ -85,3 +186,93 @@ func main() {
 
 	log.Println("✅ Server exited gracefully")
 }
+
+// newTrapReceiver creates the SNMP trap receiver configured in a traps file,
+// decoding traps with the OID profile of the sending device
+func newTrapReceiver(path string, devices *config.DeviceRegistry) (*olt.TrapReceiver, string, error) {
+	cfg, err := config.LoadTrapConfig(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	opts := olt.TrapOptions{
+		Communities: cfg.Communities,
+		BufferSize:  cfg.BufferSize,
+		Device: func(host string) (string, string, string, string) {
+			if d, ok := devices.Get(host); ok {
+				return d.Vendor, d.Model, d.Firmware, d.OIDProfile
+			}
+			return "", "", "", ""
+		},
+	}
+	if v3 := cfg.V3; v3 != nil {
+		opts.V3 = &olt.SNMPv3Credentials{
+			Username:      v3.Username,
+			SecurityLevel: v3.SecurityLevel,
+			AuthProtocol:  v3.AuthProtocol,
+			AuthPassword:  v3.AuthPassword,
+			PrivProtocol:  v3.PrivProtocol,
+			PrivPassword:  v3.PrivPassword,
+		}
+		opts.EngineID = v3.EngineID
+	}
+
+	for _, s := range cfg.Sinks {
+		var sink olt.TrapSink
+		switch s.Type {
+		case "webhook":
+			sink = olt.NewWebhookSink(s.URL, s.Headers, time.Duration(s.Timeout)*time.Second)
+		case "file":
+			if sink, err = olt.NewFileSink(s.Path); err != nil {
+				return nil, "", err
+			}
+		default:
+			sink = olt.NewLogSink()
+		}
+
+		events := make([]olt.TrapEventType, 0, len(s.Events))
+		for _, event := range s.Events {
+			events = append(events, olt.TrapEventType(event))
+		}
+		opts.Sinks = append(opts.Sinks, olt.TrapSinkConfig{Sink: sink, Events: events})
+	}
+
+	receiver, err := olt.NewTrapReceiver(opts)
+	return receiver, cfg.Listen, err
+}
+
+// runBulkAdd provisions ONUs listed in a CSV or JSON file and writes the report as CSV to stdout
+func runBulkAdd(handlers *api.Handlers, path string, renderOnly bool) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("❌ Failed to read bulk file: %v", err)
+		return 1
+	}
+
+	format := "csv"
+	if strings.HasSuffix(strings.ToLower(path), ".json") {
+		format = "json"
+	}
+
+	req, err := api.ParseBulkAddRows(data, format)
+	if err != nil {
+		log.Printf("❌ Failed to parse bulk file: %v", err)
+		return 1
+	}
+	req.RenderOnly = req.RenderOnly || renderOnly
+
+	log.Printf("📦 Provisioning %d ONUs from %s", len(req.Rows), path)
+	result := handlers.RunBulkAdd(context.Background(), req)
+
+	if err := api.WriteBulkAddReportCSV(os.Stdout, result.Results); err != nil {
+		log.Printf("❌ Failed to write report: %v", err)
+		return 1
+	}
+
+	log.Printf("✅ Done in %s: %d succeeded, %d failed, %d invalid",
+		result.Time, result.Succeeded, result.Failed, result.Invalid)
+	if result.Failed > 0 || result.Invalid > 0 {
+		return 2
+	}
+	return 0
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"strings"
 	"time"
 
@@ -15,16 +17,26 @@ import (
 
 // Handlers holds API handlers
 type Handlers struct {
-	oltService   *olt.Service
-	templateMgr  *config.TemplateManager
-	requestIDGen func() string
+	oltService      *olt.Service
+	templateMgr     *config.TemplateManager
+	profiles        *config.ProfileRegistry
+	devices         *config.DeviceRegistry
+	thresholds      *config.ThresholdRegistry
+	traps           *olt.TrapReceiver // nil when the trap receiver is disabled
+	parallelWorkers int
+	requestIDGen    func() string
 }
 
 // NewHandlers creates new API handlers
-func NewHandlers(oltService *olt.Service, templateMgr *config.TemplateManager) *Handlers {
+func NewHandlers(cfg *config.Config, oltService *olt.Service, templateMgr *config.TemplateManager, profiles *config.ProfileRegistry, devices *config.DeviceRegistry, thresholds *config.ThresholdRegistry, traps *olt.TrapReceiver) *Handlers {
 	return &Handlers{
-		oltService:  oltService,
-		templateMgr: templateMgr,
+		oltService:      oltService,
+		templateMgr:     templateMgr,
+		profiles:        profiles,
+		devices:         devices,
+		thresholds:      thresholds,
+		traps:           traps,
+		parallelWorkers: cfg.OLT.ParallelWorkers,
 		requestIDGen: func() string {
 			return fmt.Sprintf("%d", time.Now().UnixNano())
 		},
@@ -43,6 +55,69 @@ func (h *Handlers) createAPIResponse(success bool, data any, errorMsg string) AP
 	return response
 }
 
+// templateErrorResponse maps a template rendering error to an API response,
+// reporting parameter validation failures and unsupported vendors as bad requests
+func (h *Handlers) templateErrorResponse(c *fiber.Ctx, err error) error {
+	var verr *config.ValidationError
+	if errors.As(err, &verr) {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, verr.Errors, verr.Error()))
+	}
+	if errors.Is(err, olt.ErrUnsupportedVendor) {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+	if errors.Is(err, config.ErrTemplateNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(
+		h.createAPIResponse(false, nil, fmt.Sprintf("Template rendering failed: %v", err)))
+}
+
+// device returns the OLT a request targets, filling in the vendor, model and
+// firmware the request leaves empty and the time zone from the device
+// registry; the registered OID profile is kept unless the request names
+// another vendor or model
+func (h *Handlers) device(host, vendor, model, firmware string) config.Device {
+	dev := config.Device{Host: host, Vendor: vendor, Model: model, Firmware: firmware}
+	if d, ok := h.devices.Get(host); ok {
+		dev.TimeZone = d.TimeZone
+		if dev.Vendor == "" {
+			dev.Vendor = d.Vendor
+		}
+		if dev.Model == "" {
+			dev.Model = d.Model
+			if dev.Firmware == "" {
+				dev.Firmware = d.Firmware
+			}
+		}
+		if dev.Vendor == d.Vendor && dev.Model == d.Model {
+			dev.OIDProfile = d.OIDProfile
+		}
+	}
+	return dev
+}
+
+// templateFor returns the template implementing op on an OLT, as named by the
+// vendor driver and resolved to the variant for the OLT model and firmware
+func (h *Handlers) templateFor(op string, dev config.Device) (string, error) {
+	driver, err := olt.GetDriver(dev.Vendor)
+	if err != nil {
+		return "", err
+	}
+	return h.templateMgr.Resolve(driver.Template(op), dev.Model, dev.Firmware)
+}
+
+// commandError returns the first CLI error in output according to the vendor driver
+func commandError(vendor, output string) string {
+	driver, err := olt.GetDriver(vendor)
+	if err != nil {
+		return ""
+	}
+	return driver.CommandError(output)
+}
+
 // AddONU handles add ONU requests
 func (h *Handlers) AddONU(c *fiber.Ctx) error {
 	var req AddONURequest
@@ -51,28 +126,22 @@ func (h *Handlers) AddONU(c *fiber.Ctx) error {
 			h.createAPIResponse(false, nil, "Invalid request body"))
 	}
 
-	// Render commands using template
-	commands, _, err := h.templateMgr.RenderTemplate("add-onu", map[string]any{
-		"Board":          req.Board,
-		"Pon":            req.PON,
-		"Onu":            req.ONU,
-		"SerialNumber":   req.SerialNumber,
-		"Name":           req.Name,
-		"SecretPassword": req.SecretPassword,
-		"Description":    req.Description,
-		"VlanID":         req.VlanID,
-		"TcontProfile":   req.TcontProfile,
-		"TrafficLimit":   req.TrafficLimit,
-	})
+	// Render commands using the template of the selected service profile
+	templateName, data, err := h.resolveAddONU(req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			h.createAPIResponse(false, nil, "Template rendering failed"))
+		return h.templateErrorResponse(c, err)
+	}
+
+	commands, _, err := h.templateMgr.RenderTemplate(templateName, data)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
 	}
 
 	if req.RenderOnly {
 		return c.JSON(h.createAPIResponse(true, ONUCommandResponse{
 			Host:       req.Host,
 			Mode:       "add-onu",
+			Template:   templateName,
 			Commands:   commands,
 			RenderOnly: true,
 			Success:    true,
@@ -85,6 +154,7 @@ func (h *Handlers) AddONU(c *fiber.Ctx) error {
 		Port:     req.Port,
 		User:     req.User,
 		Password: req.Password,
+		Vendor:   h.device(req.Host, req.Vendor, "", "").Vendor,
 		Commands: commands,
 	}
 
@@ -98,6 +168,7 @@ func (h *Handlers) AddONU(c *fiber.Ctx) error {
 	response := ONUCommandResponse{
 		Host:       result.Host,
 		Mode:       "add-onu",
+		Template:   templateName,
 		Commands:   commands,
 		Output:     result.Output,
 		Success:    result.Success,
@@ -109,6 +180,62 @@ func (h *Handlers) AddONU(c *fiber.Ctx) error {
 	return c.JSON(h.createAPIResponse(true, response, ""))
 }
 
+// addONUTemplateData builds the add-onu template data from a request. Fields
+// left empty are omitted so profile defaults and schema checks apply to them.
+func addONUTemplateData(req AddONURequest) map[string]any {
+	fields := map[string]any{
+		"Board":          req.Board,
+		"Pon":            req.PON,
+		"Onu":            req.ONU,
+		"SerialNumber":   req.SerialNumber,
+		"Name":           req.Name,
+		"SecretPassword": req.SecretPassword,
+		"Description":    req.Description,
+		"VlanID":         req.VlanID,
+		"TcontProfile":   req.TcontProfile,
+		"TrafficLimit":   req.TrafficLimit,
+	}
+
+	data := make(map[string]any, len(fields)+len(req.Params))
+	for k, v := range fields {
+		if v != 0 && v != "" {
+			data[k] = v
+		}
+	}
+	for k, v := range req.Params {
+		data[k] = v
+	}
+	return data
+}
+
+// resolveAddONU returns the template and parameters for an add ONU request by
+// applying the service profile it selects (pppoe-router when none is given) and
+// picking the template variant for the OLT vendor and model
+func (h *Handlers) resolveAddONU(req AddONURequest) (string, map[string]any, error) {
+	dev := h.device(req.Host, req.Vendor, req.Model, req.Firmware)
+
+	name := req.Profile
+	if name == "" {
+		name = config.DefaultServiceProfile
+	}
+
+	profile, err := h.profiles.Get(name)
+	if err != nil {
+		if req.Profile == "" {
+			templateName, err := h.templateFor("add-onu", dev)
+			return templateName, addONUTemplateData(req), err
+		}
+		return "", nil, &config.ValidationError{Template: "add-onu", Errors: []string{err.Error()}}
+	}
+
+	data, err := profile.Resolve(addONUTemplateData(req))
+	if err != nil {
+		return "", nil, err
+	}
+	templateName, err := h.templateFor(profile.Template, dev)
+	return templateName, data, err
+}
+
 // DeleteONU handles delete ONU requests
 func (h *Handlers) DeleteONU(c *fiber.Ctx) error {
 	var req DeleteONURequest
@@ -117,21 +244,26 @@ func (h *Handlers) DeleteONU(c *fiber.Ctx) error {
 			h.createAPIResponse(false, nil, "Invalid request body"))
 	}
 
-	// Render commands using template
-	commands, _, err := h.templateMgr.RenderTemplate("delete-onu", map[string]any{
+	// Render commands using the template for the OLT model
+	dev := h.device(req.Host, req.Vendor, req.Model, req.Firmware)
+	templateName, err := h.templateFor("delete-onu", dev)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
+	}
+	commands, _, err := h.templateMgr.RenderTemplate(templateName, map[string]any{
 		"Board": req.Board,
 		"Pon":   req.PON,
 		"Onu":   req.ONU,
 	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			h.createAPIResponse(false, nil, "Template rendering failed"))
+		return h.templateErrorResponse(c, err)
 	}
 
 	if req.RenderOnly {
 		return c.JSON(h.createAPIResponse(true, ONUCommandResponse{
 			Host:       req.Host,
 			Mode:       "delete-onu",
+			Template:   templateName,
 			Commands:   commands,
 			RenderOnly: true,
 			Success:    true,
@@ -144,6 +276,7 @@ func (h *Handlers) DeleteONU(c *fiber.Ctx) error {
 		Port:     req.Port,
 		User:     req.User,
 		Password: req.Password,
+		Vendor:   dev.Vendor,
 		Commands: commands,
 	}
 
@@ -157,6 +290,7 @@ func (h *Handlers) DeleteONU(c *fiber.Ctx) error {
 	response := ONUCommandResponse{
 		Host:       result.Host,
 		Mode:       "delete-onu",
+		Template:   templateName,
 		Commands:   commands,
 		Output:     result.Output,
 		Success:    result.Success,
@@ -176,15 +310,19 @@ func (h *Handlers) RebootONU(c *fiber.Ctx) error {
 			h.createAPIResponse(false, nil, "Invalid request body"))
 	}
 
-	// Render commands using template
-	commands, _, err := h.templateMgr.RenderTemplate("reboot-onu", map[string]any{
+	// Render commands using the template for the OLT
+	dev := h.device(req.Host, req.Vendor, req.Model, req.Firmware)
+	templateName, err := h.templateFor("reboot-onu", dev)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
+	}
+	commands, _, err := h.templateMgr.RenderTemplate(templateName, map[string]any{
 		"Board": req.Board,
 		"Pon":   req.PON,
 		"Onu":   req.ONU,
 	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			h.createAPIResponse(false, nil, "Template rendering failed"))
+		return h.templateErrorResponse(c, err)
 	}
 
 	if req.RenderOnly {
@@ -202,6 +340,7 @@ func (h *Handlers) RebootONU(c *fiber.Ctx) error {
 		Port:     req.Port,
 		User:     req.User,
 		Password: req.Password,
+		Vendor:   dev.Vendor,
 		Commands: commands,
 	}
 
@@ -232,11 +371,15 @@ func (h *Handlers) SaveConfiguration(c *fiber.Ctx) error {
 			h.createAPIResponse(false, nil, "Invalid request body"))
 	}
 
-	// Render commands using template
-	commands, _, err := h.templateMgr.RenderTemplate("save-config", nil)
+	// Render commands using the template for the OLT
+	dev := h.device(req.Host, req.Vendor, req.Model, req.Firmware)
+	templateName, err := h.templateFor("save-config", dev)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			h.createAPIResponse(false, nil, "Template rendering failed"))
+		return h.templateErrorResponse(c, err)
+	}
+	commands, _, err := h.templateMgr.RenderTemplate(templateName, nil)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
 	}
 
 	if req.RenderOnly {
@@ -261,6 +404,7 @@ func (h *Handlers) SaveConfiguration(c *fiber.Ctx) error {
 		Port:     req.Port,
 		User:     req.User,
 		Password: req.Password,
+		Vendor:   dev.Vendor,
 		Commands: commands,
 	}
 
@@ -296,8 +440,8 @@ func (h *Handlers) SaveConfiguration(c *fiber.Ctx) error {
 		status = "failed"
 		// Check for specific timeout indicators
 		if strings.Contains(result.Output, "ERR: read timeout") ||
-		   strings.Contains(result.Error, "timed out") ||
-		   strings.Contains(result.Output, "timeout") {
+			strings.Contains(result.Error, "timed out") ||
+			strings.Contains(result.Output, "timeout") {
 			status = "timeout"
 		}
 	}
@@ -340,15 +484,19 @@ func (h *Handlers) CheckAttenuation(c *fiber.Ctx) error {
 		}, ""))
 	}
 
-	// Render commands using template
-	commands, _, err := h.templateMgr.RenderTemplate("check-attenuation", map[string]any{
+	// Render commands using the template for the OLT
+	dev := h.device(req.Host, req.Vendor, req.Model, req.Firmware)
+	templateName, err := h.templateFor("check-attenuation", dev)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
+	}
+	commands, _, err := h.templateMgr.RenderTemplate(templateName, map[string]any{
 		"Board": req.Board,
 		"Pon":   req.PON,
 		"Onu":   req.ONU,
 	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			h.createAPIResponse(false, nil, "Template rendering failed"))
+		return h.templateErrorResponse(c, err)
 	}
 
 	// Execute commands on OLT
@@ -357,6 +505,7 @@ func (h *Handlers) CheckAttenuation(c *fiber.Ctx) error {
 		Port:     req.Port,
 		User:     req.User,
 		Password: req.Password,
+		Vendor:   dev.Vendor,
 		Commands: commands,
 	}
 
@@ -370,12 +519,15 @@ func (h *Handlers) CheckAttenuation(c *fiber.Ctx) error {
 	// Parse the output to extract structured attenuation data
 	var attenuationData *AttenuationDataDTO
 	if result.Success && result.Output != "" {
-		// Extract the actual output (remove headers and prompts)
-		actualOutput := extractAttenuationOutput(result.Output)
-		parsedData := utils.ParseAttenuationOutput(req.Host, req.Board, req.PON, req.ONU, actualOutput)
+		// Parse with the vendor driver, already validated by templateFor
+		driver, _ := olt.GetDriver(dev.Vendor)
+		parsedData := driver.ParseOptical(req.Host, req.Board, req.PON, req.ONU, result.Output)
 
 		// Convert to DTO
 		if parsedData != nil {
+			profile, thresholds := h.thresholds.For(req.Host, req.Board, req.PON)
+			parsedData.Classify(thresholds)
+
 			attenuationData = &AttenuationDataDTO{
 				Host:        parsedData.Host,
 				Board:       parsedData.Board,
@@ -388,6 +540,8 @@ func (h *Handlers) CheckAttenuation(c *fiber.Ctx) error {
 				ONUTxPower:  parsedData.ONUTxPower,
 				Attenuation: parsedData.Attenuation,
 				Status:      parsedData.Status,
+				RxStatus:    parsedData.RxStatus,
+				Thresholds:  profile,
 				RawOutput:   parsedData.RawOutput,
 			}
 		}
@@ -406,36 +560,6 @@ func (h *Handlers) CheckAttenuation(c *fiber.Ctx) error {
 	return c.JSON(h.createAPIResponse(true, response, ""))
 }
 
-// extractAttenuationOutput extracts the actual attenuation data from the full command output
-func extractAttenuationOutput(fullOutput string) string {
-	lines := strings.Split(fullOutput, "\n")
-	var outputLines []string
-	inData := false
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Skip headers and prompts
-		if strings.Contains(line, "===") || strings.HasPrefix(line, ">>>") ||
-			strings.HasPrefix(line, "ZXAN") || line == "" {
-			continue
-		}
-
-		// Start collecting data when we see relevant content
-		if strings.Contains(line, "OLT") || strings.Contains(line, "ONU") ||
-			strings.Contains(line, "Attenuation") || strings.Contains(line, "up") ||
-			strings.Contains(line, "down") {
-			inData = true
-		}
-
-		if inData {
-			outputLines = append(outputLines, line)
-		}
-	}
-
-	return strings.Join(outputLines, "\n")
-}
-
 // CheckUnconfigured handles check unconfigured ONU requests
 func (h *Handlers) CheckUnconfigured(c *fiber.Ctx) error {
 	var req CheckUnconfiguredRequest
@@ -453,11 +577,15 @@ func (h *Handlers) CheckUnconfigured(c *fiber.Ctx) error {
 		}, ""))
 	}
 
-	// Render commands using template
-	commands, _, err := h.templateMgr.RenderTemplate("check-unconfigured", nil)
+	// Render commands using the template for the OLT
+	dev := h.device(req.Host, req.Vendor, req.Model, req.Firmware)
+	templateName, err := h.templateFor("check-unconfigured", dev)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			h.createAPIResponse(false, nil, "Template rendering failed"))
+		return h.templateErrorResponse(c, err)
+	}
+	commands, _, err := h.templateMgr.RenderTemplate(templateName, nil)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
 	}
 
 	// Execute commands on OLT
@@ -466,6 +594,7 @@ func (h *Handlers) CheckUnconfigured(c *fiber.Ctx) error {
 		Port:     req.Port,
 		User:     req.User,
 		Password: req.Password,
+		Vendor:   dev.Vendor,
 		Commands: commands,
 	}
 
@@ -479,21 +608,15 @@ func (h *Handlers) CheckUnconfigured(c *fiber.Ctx) error {
 	// Parse the output to extract structured unconfigured ONU data
 	var unconfiguredData *UnconfiguredONUListDTO
 	if result.Success && result.Output != "" {
-		// Extract the actual output (remove headers and prompts)
-		actualOutput := extractUnconfiguredOutput(result.Output)
-		parsedData := utils.ParseUnconfiguredONUOutput(req.Host, actualOutput)
+		// Parse with the vendor driver, already validated by templateFor
+		driver, _ := olt.GetDriver(dev.Vendor)
+		parsedData := driver.ParseUnconfigured(req.Host, result.Output)
 
 		// Convert to DTO
 		if parsedData != nil {
 			onus := make([]UnconfiguredONUDTO, len(parsedData.ONUs))
 			for i, onu := range parsedData.ONUs {
-				onus[i] = UnconfiguredONUDTO{
-					OLTIndex:     onu.OLTIndex,
-					Model:        onu.Model,
-					SerialNumber: onu.SerialNumber,
-					Board:        onu.Board,
-					PON:          onu.PON,
-				}
+				onus[i] = toUnconfiguredONUDTO(onu)
 			}
 
 			// Convert grouped by slot
@@ -501,13 +624,7 @@ func (h *Handlers) CheckUnconfigured(c *fiber.Ctx) error {
 			for slot, slotONUs := range parsedData.GroupedBySlot {
 				converted := make([]UnconfiguredONUDTO, len(slotONUs))
 				for i, onu := range slotONUs {
-					converted[i] = UnconfiguredONUDTO{
-						OLTIndex:     onu.OLTIndex,
-						Model:        onu.Model,
-						SerialNumber: onu.SerialNumber,
-						Board:        onu.Board,
-						PON:          onu.PON,
-					}
+					converted[i] = toUnconfiguredONUDTO(onu)
 				}
 				grouped[slot] = converted
 			}
@@ -536,33 +653,31 @@ func (h *Handlers) CheckUnconfigured(c *fiber.Ctx) error {
 	return c.JSON(h.createAPIResponse(true, response, ""))
 }
 
-// extractUnconfiguredOutput extracts the actual unconfigured ONU data from the full command output
-func extractUnconfiguredOutput(fullOutput string) string {
-	lines := strings.Split(fullOutput, "\n")
-	var outputLines []string
-	inData := false
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Skip headers and prompts
-		if strings.Contains(line, "===") || strings.HasPrefix(line, ">>>") ||
-			strings.HasPrefix(line, "ZXAN") || line == "" {
-			continue
-		}
-
-		// Start collecting data when we see relevant content
-		if strings.Contains(line, "OltIndex") || strings.Contains(line, "Model") ||
-			strings.Contains(line, "SN") || strings.Contains(line, "gpon-olt_") {
-			inData = true
-		}
-
-		if inData {
-			outputLines = append(outputLines, line)
-		}
-	}
-
-	return strings.Join(outputLines, "\n")
+// toUnconfiguredONUDTO converts a parsed unconfigured ONU, adding its catalog entry
+func toUnconfiguredONUDTO(onu utils.UnconfiguredONU) UnconfiguredONUDTO {
+	dto := UnconfiguredONUDTO{
+		OLTIndex:     onu.OLTIndex,
+		Technology:   onu.Technology,
+		Model:        onu.Model,
+		SerialNumber: onu.SerialNumber,
+		MACAddress:   onu.MACAddress,
+		Password:     onu.Password,
+		LOID:         onu.LOID,
+		LOIDPassword: onu.LOIDPassword,
+		State:        onu.State,
+		Rack:         onu.Rack,
+		Shelf:        onu.Shelf,
+		Slot:         onu.Slot,
+		Port:         onu.Port,
+		ONU:          onu.ONU,
+		Board:        onu.Board,
+		PON:          onu.PON,
+	}
+	if m, ok := utils.LookupONUModel(onu.Model); ok {
+		dto.ModelVendor = m.Vendor
+		dto.ModelDescription = m.Description
+	}
+	return dto
 }
 
 // BatchCommands handles batch command requests
@@ -579,6 +694,7 @@ func (h *Handlers) BatchCommands(c *fiber.Ctx) error {
 		Port:     req.Port,
 		User:     req.User,
 		Password: req.Password,
+		Vendor:   h.device(req.Host, req.Vendor, "", "").Vendor,
 		Commands: req.Commands,
 	}
 
@@ -604,13 +720,18 @@ func (h *Handlers) BatchCommands(c *fiber.Ctx) error {
 
 // HealthCheck handles health check requests
 func (h *Handlers) HealthCheck(c *fiber.Ctx) error {
+	templatesStatus := "ok"
+	if len(h.templateMgr.GetLoadErrors()) > 0 {
+		templatesStatus = "degraded"
+	}
+
 	response := HealthCheckResponse{
 		Status:  "healthy",
 		Version: "1.0.0",
 		Uptime:  "0h 0m 0s", // TODO: Calculate actual uptime
 		Services: map[string]string{
 			"olt":       "ok",
-			"templates": "ok",
+			"templates": templatesStatus,
 			"fiber":     "ok",
 		},
 		Timestamp: time.Now(),
@@ -622,10 +743,114 @@ func (h *Handlers) HealthCheck(c *fiber.Ctx) error {
 // ListTemplates handles template listing requests
 func (h *Handlers) ListTemplates(c *fiber.Ctx) error {
 	templates := h.templateMgr.GetAvailableTemplates()
+	loadErrors := h.templateMgr.GetLoadErrors()
 
 	data := map[string]any{
 		"templates": templates,
 		"count":     len(templates),
+		"details":   h.templateMgr.GetTemplateInfo(),
+	}
+	if len(loadErrors) > 0 {
+		data["errors"] = loadErrors
+	}
+
+	return c.JSON(h.createAPIResponse(true, data, ""))
+}
+
+// GetTemplate handles template detail requests, including the parameter schema.
+// The optional model and firmware query parameters select the model variant.
+func (h *Handlers) GetTemplate(c *fiber.Ctx) error {
+	name, err := h.templateMgr.Resolve(templateNameParam(c), c.Query("model"), c.Query("firmware"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+	detail, err := h.templateMgr.GetTemplate(name)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+
+	return c.JSON(h.createAPIResponse(true, detail, ""))
+}
+
+// templateNameParam returns the unescaped :name route parameter, so nested
+// template names can be addressed as e.g. c600%2Fadd-onu
+func templateNameParam(c *fiber.Ctx) string {
+	name := c.Params("name")
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		return unescaped
+	}
+	return name
+}
+
+// ListProfiles handles service profile listing requests
+func (h *Handlers) ListProfiles(c *fiber.Ctx) error {
+	profiles := h.profiles.List()
+
+	data := map[string]any{
+		"profiles": profiles,
+		"count":    len(profiles),
+		"default":  config.DefaultServiceProfile,
+	}
+
+	return c.JSON(h.createAPIResponse(true, data, ""))
+}
+
+// GetProfile handles service profile detail requests, including the template schema
+func (h *Handlers) GetProfile(c *fiber.Ctx) error {
+	profile, err := h.profiles.Get(c.Params("name"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+
+	data := map[string]any{
+		"profile": profile,
+	}
+	if detail, err := h.templateMgr.GetTemplate(profile.Template); err == nil {
+		data["schema"] = detail.Schema
+	}
+
+	return c.JSON(h.createAPIResponse(true, data, ""))
+}
+
+// ListDevices handles device inventory listing requests
+func (h *Handlers) ListDevices(c *fiber.Ctx) error {
+	devices := h.devices.List()
+	for i := range devices {
+		devices[i] = devices[i].Redacted()
+	}
+
+	data := map[string]any{
+		"devices": devices,
+		"count":   len(devices),
+		"vendors": olt.Vendors(),
+	}
+
+	return c.JSON(h.createAPIResponse(true, data, ""))
+}
+
+// ListThresholds handles optical threshold profile listing requests
+func (h *Handlers) ListThresholds(c *fiber.Ctx) error {
+	fallback, olts := h.thresholds.Assignments()
+
+	data := map[string]any{
+		"default":  fallback,
+		"profiles": h.thresholds.Profiles(),
+		"olts":     olts,
+	}
+
+	return c.JSON(h.createAPIResponse(true, data, ""))
+}
+
+// ListOIDProfiles handles SNMP OID profile listing requests
+func (h *Handlers) ListOIDProfiles(c *fiber.Ctx) error {
+	profiles := olt.OIDProfiles()
+
+	data := map[string]any{
+		"profiles": profiles,
+		"count":    len(profiles),
 	}
 
 	return c.JSON(h.createAPIResponse(true, data, ""))
@@ -639,15 +864,36 @@ func (h *Handlers) APIInfo(c *fiber.Ctx) error {
 		"status":    "running",
 		"framework": "Fiber v2",
 		"endpoints": map[string]string{
-			"health":               "/api/v1/health",
-			"templates":            "/api/v1/templates",
-			"add_onu":              "/api/v1/onu/add",
-			"delete_onu":           "/api/v1/onu/delete",
-			"reboot_onu":           "/api/v1/onu/reboot",
-			"check_attenuation":    "/api/v1/onu/check-attenuation",
-			"check_unconfigured":   "/api/v1/onu/check-unconfigured",
-			"save_configuration":   "/api/v1/system/save-configuration",
-			"batch_commands":       "/api/v1/batch/commands",
+			"health":             "/api/v1/health",
+			"templates":          "/api/v1/templates",
+			"template_detail":    "/api/v1/templates/:name",
+			"template_execute":   "/api/v1/templates/:name/execute",
+			"template_render":    "/api/v1/templates/:name/render",
+			"template_revisions": "/api/v1/templates/:name/revisions",
+			"service_profiles":   "/api/v1/profiles",
+			"devices":            "/api/v1/devices",
+			"thresholds":         "/api/v1/thresholds",
+			"oid_profiles":       "/api/v1/snmp/oid-profiles",
+			"trap_events":        "/api/v1/snmp/traps/events",
+			"trap_stats":         "/api/v1/snmp/traps/stats",
+			"add_onu":            "/api/v1/onu/add",
+			"delete_onu":         "/api/v1/onu/delete",
+			"reboot_onu":         "/api/v1/onu/reboot",
+			"check_attenuation":  "/api/v1/onu/check-attenuation",
+			"check_unconfigured": "/api/v1/onu/check-unconfigured",
+			"onu_state":          "/api/v1/onu/state",
+			"onu_detail":         "/api/v1/onu/detail",
+			"pon_power":          "/api/v1/pon/power",
+			"olt_inventory":      "/api/v1/olt/inventory/snmp",
+			"offline_report":     "/api/v1/olt/offline-report/snmp",
+			"pon_traffic":        "/api/v1/board/:board_id/pon/:pon_id/traffic/snmp",
+			"onu_traffic":        "/api/v1/board/:board_id/pon/:pon_id/onu/:onu_id/traffic/snmp",
+			"pon_diagnostics":    "/api/v1/board/:board_id/pon/:pon_id/diagnostics/snmp",
+			"olt_diagnostics":    "/api/v1/olt/diagnostics/snmp",
+			"olt_system":         "/api/v1/olt/system/snmp",
+			"save_configuration": "/api/v1/system/save-configuration",
+			"batch_commands":     "/api/v1/batch/commands",
+			"bulk_add_onu":       "/api/v1/onu/bulk-add",
 		},
 	}
 
@@ -0,0 +1,493 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/achyar10/go-zteolt/internal/config"
+	"github.com/achyar10/go-zteolt/internal/olt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// BulkAddONU handles bulk ONU provisioning from a JSON array or CSV upload
+func (h *Handlers) BulkAddONU(c *fiber.Ctx) error {
+	req, err := parseBulkAddRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+
+	if len(req.Rows) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "No rows supplied"))
+	}
+
+	response := h.RunBulkAdd(c.Context(), req)
+
+	if strings.EqualFold(c.Query("format"), "csv") {
+		var buf bytes.Buffer
+		if err := WriteBulkAddReportCSV(&buf, response.Results); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				h.createAPIResponse(false, nil, fmt.Sprintf("Failed to write report: %v", err)))
+		}
+		c.Set(fiber.HeaderContentType, "text/csv")
+		c.Set(fiber.HeaderContentDisposition,
+			fmt.Sprintf(`attachment; filename="bulk-add-%s.csv"`, time.Now().Format("20060102-150405")))
+		return c.Send(buf.Bytes())
+	}
+
+	if response.Invalid > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, response, "Validation failed, no rows were executed"))
+	}
+
+	return c.JSON(h.createAPIResponse(true, response, ""))
+}
+
+// RunBulkAdd validates every row upfront and, if all rows are valid, provisions them
+// grouped by OLT with at most req.Workers concurrent sessions per device
+func (h *Handlers) RunBulkAdd(ctx context.Context, req BulkAddONURequest) *BulkAddONUResponse {
+	start := time.Now()
+
+	results := make([]BulkAddRowResult, len(req.Rows))
+	invalid := 0
+	for i, row := range h.validateBulkRows(req.Rows) {
+		results[i] = newBulkAddRowResult(i, req.Rows[i])
+		if len(row) > 0 {
+			results[i].Status = "invalid"
+			results[i].Error = strings.Join(row, "; ")
+			invalid++
+		}
+	}
+
+	response := &BulkAddONUResponse{
+		Total:      len(req.Rows),
+		Invalid:    invalid,
+		RenderOnly: req.RenderOnly,
+		Results:    results,
+	}
+
+	if invalid > 0 {
+		for i := range results {
+			if results[i].Status == "" {
+				results[i].Status = "skipped"
+			}
+		}
+		response.Time = time.Since(start).String()
+		return response
+	}
+
+	workers := req.Workers
+	if workers <= 0 {
+		workers = h.parallelWorkers
+	}
+
+	// Group rows by device so each OLT gets its own bounded worker pool
+	groups := make(map[string][]int)
+	for i, row := range req.Rows {
+		key := fmt.Sprintf("%s:%d", row.Host, row.Port)
+		groups[key] = append(groups[key], i)
+	}
+
+	var wg sync.WaitGroup
+	for _, indexes := range groups {
+		wg.Add(1)
+		go func(indexes []int) {
+			defer wg.Done()
+
+			sem := make(chan struct{}, workers)
+			var deviceWG sync.WaitGroup
+			for _, idx := range indexes {
+				deviceWG.Add(1)
+				sem <- struct{}{}
+				go func(idx int) {
+					defer deviceWG.Done()
+					defer func() { <-sem }()
+					h.executeBulkRow(ctx, req.Rows[idx], req.RenderOnly, &results[idx])
+				}(idx)
+			}
+			deviceWG.Wait()
+		}(indexes)
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		switch r.Status {
+		case "success", "rendered":
+			response.Succeeded++
+		case "failed":
+			response.Failed++
+		}
+	}
+	response.Time = time.Since(start).String()
+
+	return response
+}
+
+// executeBulkRow renders and executes a single bulk row, recording the outcome in result
+func (h *Handlers) executeBulkRow(ctx context.Context, row AddONURequest, renderOnly bool, result *BulkAddRowResult) {
+	start := time.Now()
+	defer func() { result.Time = time.Since(start).String() }()
+
+	templateName, data, err := h.resolveAddONU(row)
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		return
+	}
+
+	commands, _, err := h.templateMgr.RenderTemplate(templateName, data)
+	if err != nil {
+		result.Status = "failed"
+		result.Error = fmt.Sprintf("template rendering failed: %v", err)
+		return
+	}
+
+	if renderOnly || row.RenderOnly {
+		result.Status = "rendered"
+		result.Commands = commands
+		return
+	}
+
+	vendor := h.device(row.Host, row.Vendor, "", "").Vendor
+	oltResult, err := h.oltService.ExecuteCommands(ctx, olt.OLTRequest{
+		Host:     row.Host,
+		Port:     row.Port,
+		User:     row.User,
+		Password: row.Password,
+		Vendor:   vendor,
+		Commands: commands,
+	})
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		return
+	}
+
+	if !oltResult.Success {
+		result.Status = "failed"
+		result.Error = oltResult.Error
+		return
+	}
+
+	if cmdErr := commandError(vendor, oltResult.Output); cmdErr != "" {
+		result.Status = "failed"
+		result.Error = cmdErr
+		return
+	}
+
+	result.Status = "success"
+}
+
+// newBulkAddRowResult creates an empty outcome for the row at index i
+func newBulkAddRowResult(i int, row AddONURequest) BulkAddRowResult {
+	return BulkAddRowResult{
+		Row:          i + 1,
+		Host:         row.Host,
+		Board:        row.Board,
+		PON:          row.PON,
+		ONU:          row.ONU,
+		SerialNumber: row.SerialNumber,
+		Name:         row.Name,
+	}
+}
+
+// validateBulkRows validates all rows and returns the validation errors per row
+func (h *Handlers) validateBulkRows(rows []AddONURequest) [][]string {
+	errs := make([][]string, len(rows))
+	slots := make(map[string]int)
+	serials := make(map[string]int)
+
+	for i, row := range rows {
+		errs[i] = h.validateAddONURequest(row)
+
+		slot := fmt.Sprintf("%s:%d/%d/%d", row.Host, row.Board, row.PON, row.ONU)
+		if prev, ok := slots[slot]; ok {
+			errs[i] = append(errs[i], fmt.Sprintf("duplicate ONU slot, already used by row %d", prev+1))
+		} else {
+			slots[slot] = i
+		}
+
+		sn := strings.ToUpper(row.SerialNumber)
+		if prev, ok := serials[sn]; ok && sn != "" {
+			errs[i] = append(errs[i], fmt.Sprintf("duplicate serial_number, already used by row %d", prev+1))
+		} else {
+			serials[sn] = i
+		}
+	}
+
+	return errs
+}
+
+// validateAddONURequest checks the connection fields of an add ONU request and
+// validates the template parameters against the schema of its service profile template
+func (h *Handlers) validateAddONURequest(req AddONURequest) []string {
+	var errs []string
+
+	if strings.TrimSpace(req.Host) == "" {
+		errs = append(errs, "host is required")
+	}
+	if req.Port < 1 || req.Port > 65535 {
+		errs = append(errs, "port must be between 1 and 65535")
+	}
+	if strings.TrimSpace(req.User) == "" {
+		errs = append(errs, "user is required")
+	}
+	if strings.TrimSpace(req.Password) == "" {
+		errs = append(errs, "password is required")
+	}
+
+	templateName, data, err := h.resolveAddONU(req)
+	if err == nil {
+		var schema *config.TemplateSchema
+		if schema, err = h.templateMgr.GetSchema(templateName); err == nil && schema != nil {
+			errs = append(errs, unknownParams(req.Params, schema, templateName)...)
+			_, err = schema.Apply(templateName, data)
+		}
+	}
+	if err != nil {
+		var verr *config.ValidationError
+		if errors.As(err, &verr) {
+			return append(errs, verr.Errors...)
+		}
+		return append(errs, err.Error())
+	}
+
+	return errs
+}
+
+// unknownParams reports the extra parameters, such as misspelled CSV columns,
+// that the template schema does not declare
+func unknownParams(params map[string]any, schema *config.TemplateSchema, templateName string) []string {
+	var errs []string
+	for name := range params {
+		if !schema.HasParam(name) {
+			errs = append(errs, fmt.Sprintf("%s is neither an add ONU field nor a parameter of %s", name, templateName))
+		}
+	}
+	sort.Strings(errs)
+	return errs
+}
+
+// parseBulkAddRequest reads bulk rows from a multipart file, CSV body or JSON body
+func parseBulkAddRequest(c *fiber.Ctx) (BulkAddONURequest, error) {
+	var req BulkAddONURequest
+
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			return req, fmt.Errorf("failed to open uploaded file: %v", err)
+		}
+		defer f.Close()
+
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return req, fmt.Errorf("failed to read uploaded file: %v", err)
+		}
+
+		format := "csv"
+		if strings.HasSuffix(strings.ToLower(file.Filename), ".json") {
+			format = "json"
+		}
+		if req, err = ParseBulkAddRows(data, format); err != nil {
+			return req, err
+		}
+		req.RenderOnly = c.FormValue("render_only") == "true"
+		req.Workers, _ = strconv.Atoi(c.FormValue("workers"))
+		return req, nil
+	}
+
+	format := "json"
+	if strings.Contains(strings.ToLower(string(c.Request().Header.ContentType())), "csv") {
+		format = "csv"
+	}
+
+	req, err := ParseBulkAddRows(c.Body(), format)
+	if err != nil {
+		return req, err
+	}
+	if c.Query("render_only") == "true" {
+		req.RenderOnly = true
+	}
+	if workers, err := strconv.Atoi(c.Query("workers")); err == nil {
+		req.Workers = workers
+	}
+	return req, nil
+}
+
+// ParseBulkAddRows parses bulk rows in "csv" or "json" format. JSON input may be
+// either a bare array of AddONURequest objects or a BulkAddONURequest object.
+func ParseBulkAddRows(data []byte, format string) (BulkAddONURequest, error) {
+	var req BulkAddONURequest
+
+	if format == "csv" {
+		rows, err := parseBulkAddCSV(data)
+		if err != nil {
+			return req, err
+		}
+		req.Rows = rows
+		return req, nil
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &req.Rows); err != nil {
+			return req, fmt.Errorf("invalid JSON rows: %v", err)
+		}
+		return req, nil
+	}
+
+	if err := json.Unmarshal(trimmed, &req); err != nil {
+		return req, fmt.Errorf("invalid JSON request: %v", err)
+	}
+	return req, nil
+}
+
+// parseBulkAddCSV parses CSV data whose header row uses the AddONURequest JSON field
+// names; extra columns are passed as parameters of the row's template
+func parseBulkAddCSV(data []byte) ([]AddONURequest, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("CSV must contain a header row and at least one data row")
+	}
+
+	header := make([]string, len(records[0]))
+	for i, col := range records[0] {
+		header[i] = strings.TrimSpace(col)
+	}
+
+	var rows []AddONURequest
+	for line, record := range records[1:] {
+		var row AddONURequest
+		for i, value := range record {
+			if i >= len(header) {
+				break
+			}
+			if err := setAddONUField(&row, header[i], strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("CSV line %d: %v", line+2, err)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// setAddONUField assigns a CSV column value to the matching AddONURequest field
+func setAddONUField(req *AddONURequest, column, value string) error {
+	key := strings.ToLower(column)
+	atoi := func() (int, error) {
+		if value == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("column %s: %q is not a number", column, value)
+		}
+		return n, nil
+	}
+
+	var err error
+	switch key {
+	case "host":
+		req.Host = value
+	case "port":
+		req.Port, err = atoi()
+	case "user":
+		req.User = value
+	case "password":
+		req.Password = value
+	case "vendor":
+		req.Vendor = value
+	case "model":
+		req.Model = value
+	case "firmware":
+		req.Firmware = value
+	case "board":
+		req.Board, err = atoi()
+	case "pon":
+		req.PON, err = atoi()
+	case "onu":
+		req.ONU, err = atoi()
+	case "serial_number":
+		req.SerialNumber = value
+	case "name":
+		req.Name = value
+	case "secret_password":
+		req.SecretPassword = value
+	case "description":
+		req.Description = value
+	case "vlan_id":
+		req.VlanID, err = atoi()
+	case "tcont_profile":
+		req.TcontProfile = value
+	case "traffic_limit":
+		req.TrafficLimit = value
+	case "profile":
+		req.Profile = value
+	case "render_only":
+		if value != "" {
+			if req.RenderOnly, err = strconv.ParseBool(value); err != nil {
+				err = fmt.Errorf("column %s: %q is not a boolean", column, value)
+			}
+		}
+	default:
+		// Any other column is a service profile parameter, e.g. IptvVlan; those
+		// the template does not declare are rejected when the row is validated
+		if value != "" {
+			if req.Params == nil {
+				req.Params = make(map[string]any)
+			}
+			req.Params[column] = value
+		}
+	}
+	return err
+}
+
+// WriteBulkAddReportCSV writes the per-row bulk provisioning outcome as CSV
+func WriteBulkAddReportCSV(w io.Writer, results []BulkAddRowResult) error {
+	sorted := make([]BulkAddRowResult, len(results))
+	copy(sorted, results)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Row < sorted[j].Row })
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"row", "host", "board", "pon", "onu", "serial_number", "name", "status", "error", "execution_time"}); err != nil {
+		return err
+	}
+	for _, r := range sorted {
+		record := []string{
+			strconv.Itoa(r.Row),
+			r.Host,
+			strconv.Itoa(r.Board),
+			strconv.Itoa(r.PON),
+			strconv.Itoa(r.ONU),
+			r.SerialNumber,
+			r.Name,
+			r.Status,
+			r.Error,
+			r.Time,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
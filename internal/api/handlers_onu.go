@@ -0,0 +1,367 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/achyar10/go-zteolt/internal/olt"
+	"github.com/achyar10/go-zteolt/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ONUState handles requests for the admin/OMCC/phase state of the ONUs on a
+// PON port, or on the whole OLT when no PON is given
+func (h *Handlers) ONUState(c *fiber.Ctx) error {
+	var req ONUStateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid request body"))
+	}
+
+	if req.PON > 0 && req.Board == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "board is required when pon is set"))
+	}
+
+	// Render commands using the template for the OLT; without a PON the whole
+	// OLT is listed and filtered by board afterwards
+	dev := h.device(req.Host, req.Vendor, req.Model, req.Firmware)
+	templateName, err := h.templateFor("check-onu-state", dev)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
+	}
+	params := map[string]any{}
+	if req.PON > 0 {
+		params["Board"] = req.Board
+		params["Pon"] = req.PON
+	}
+	commands, _, err := h.templateMgr.RenderTemplate(templateName, params)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
+	}
+
+	if req.RenderOnly {
+		return c.JSON(h.createAPIResponse(true, ONUStateResponse{
+			Host:       req.Host,
+			Mode:       "onu-state",
+			Template:   templateName,
+			Commands:   commands,
+			RenderOnly: true,
+			Success:    true,
+		}, ""))
+	}
+
+	// Execute commands on OLT
+	oltReq := olt.OLTRequest{
+		Host:     req.Host,
+		Port:     req.Port,
+		User:     req.User,
+		Password: req.Password,
+		Vendor:   dev.Vendor,
+		Commands: commands,
+	}
+
+	ctx := c.Context()
+	result, err := h.oltService.ExecuteCommands(ctx, oltReq)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+
+	response := ONUStateResponse{
+		Host:     result.Host,
+		Mode:     "onu-state",
+		Template: templateName,
+		Commands: commands,
+		Success:  result.Success,
+		Error:    result.Error,
+		Time:     result.Time,
+	}
+	if response.Success {
+		if cmdErr := commandError(dev.Vendor, result.Output); cmdErr != "" {
+			response.Success = false
+			response.Error = cmdErr
+		}
+	}
+
+	// Parse with the vendor driver, already validated by templateFor
+	if response.Success && result.Output != "" {
+		driver, _ := olt.GetDriver(dev.Vendor)
+		parsed := driver.ParseONUState(req.Host, result.Output)
+
+		// The board filter narrows the scope of the totals, state filters only the list
+		scope := utils.FilterONUStates(parsed.ONUs, req.Board, "", "", "")
+		matched := utils.FilterONUStates(scope, 0, req.State, req.AdminState, req.OMCCState)
+
+		byPhase := make(map[string]int)
+		for _, onu := range scope {
+			byPhase[strings.ToLower(onu.PhaseState)]++
+		}
+
+		onus := make([]ONUStateDTO, len(matched))
+		for i, onu := range matched {
+			onus[i] = ONUStateDTO{
+				OnuIndex:   onu.OnuIndex,
+				Board:      onu.Board,
+				PON:        onu.PON,
+				ONU:        onu.ONU,
+				AdminState: onu.AdminState,
+				OMCCState:  onu.OMCCState,
+				PhaseState: onu.PhaseState,
+				Channel:    onu.Channel,
+			}
+		}
+
+		response.Data = &ONUStateListDTO{
+			Host:         parsed.Host,
+			TotalCount:   len(scope),
+			MatchedCount: len(matched),
+			ByPhaseState: byPhase,
+			ONUs:         onus,
+			RawOutput:    parsed.RawOutput,
+		}
+	}
+
+	return c.JSON(h.createAPIResponse(true, response, ""))
+}
+
+// ONUDetail handles requests for the detail info and online/offline history of an ONU
+func (h *Handlers) ONUDetail(c *fiber.Ctx) error {
+	var req ONUDetailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid request body"))
+	}
+
+	// Render commands using the template for the OLT
+	dev := h.device(req.Host, req.Vendor, req.Model, req.Firmware)
+	templateName, err := h.templateFor("check-onu-detail", dev)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
+	}
+	commands, _, err := h.templateMgr.RenderTemplate(templateName, map[string]any{
+		"Board": req.Board,
+		"Pon":   req.PON,
+		"Onu":   req.ONU,
+	})
+	if err != nil {
+		return h.templateErrorResponse(c, err)
+	}
+
+	if req.RenderOnly {
+		return c.JSON(h.createAPIResponse(true, ONUDetailResponse{
+			Host:       req.Host,
+			Mode:       "onu-detail",
+			Template:   templateName,
+			Commands:   commands,
+			RenderOnly: true,
+			Success:    true,
+		}, ""))
+	}
+
+	// Execute commands on OLT
+	oltReq := olt.OLTRequest{
+		Host:     req.Host,
+		Port:     req.Port,
+		User:     req.User,
+		Password: req.Password,
+		Vendor:   dev.Vendor,
+		Commands: commands,
+	}
+
+	ctx := c.Context()
+	result, err := h.oltService.ExecuteCommands(ctx, oltReq)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+
+	response := ONUDetailResponse{
+		Host:     result.Host,
+		Mode:     "onu-detail",
+		Template: templateName,
+		Commands: commands,
+		Success:  result.Success,
+		Error:    result.Error,
+		Time:     result.Time,
+	}
+	if response.Success {
+		if cmdErr := commandError(dev.Vendor, result.Output); cmdErr != "" {
+			response.Success = false
+			response.Error = cmdErr
+		}
+	}
+
+	// Parse with the vendor driver, already validated by templateFor
+	if response.Success && result.Output != "" {
+		driver, _ := olt.GetDriver(dev.Vendor)
+		parsed := driver.ParseONUDetail(req.Host, result.Output)
+
+		history := make([]ONUHistoryEventDTO, len(parsed.History))
+		for i, event := range parsed.History {
+			history[i] = ONUHistoryEventDTO{
+				Index:       event.Index,
+				OnlineTime:  event.OnlineTime,
+				OfflineTime: event.OfflineTime,
+				Cause:       event.Cause,
+				Online:      event.Online,
+			}
+		}
+
+		response.Data = &ONUDetailDTO{
+			Host:                  parsed.Host,
+			Interface:             parsed.Interface,
+			Board:                 parsed.Board,
+			PON:                   parsed.PON,
+			ONU:                   parsed.ONU,
+			Name:                  parsed.Name,
+			Type:                  parsed.Type,
+			State:                 parsed.State,
+			AdminState:            parsed.AdminState,
+			PhaseState:            parsed.PhaseState,
+			ConfigState:           parsed.ConfigState,
+			AuthMode:              parsed.AuthMode,
+			SerialNumber:          parsed.SerialNumber,
+			Password:              parsed.Password,
+			Description:           parsed.Description,
+			VendorID:              parsed.VendorID,
+			VersionID:             parsed.VersionID,
+			DistanceM:             parsed.DistanceM,
+			OnlineDuration:        parsed.OnlineDuration,
+			OnlineDurationSeconds: parsed.OnlineDurationSeconds,
+			History:               history,
+			RawOutput:             parsed.RawOutput,
+		}
+	}
+
+	return c.JSON(h.createAPIResponse(true, response, ""))
+}
+
+// PONPower handles requests for the optical power of every ONU on a PON port,
+// classified by estimated attenuation and summarized as a histogram
+func (h *Handlers) PONPower(c *fiber.Ctx) error {
+	var req PONPowerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid request body"))
+	}
+
+	sortBy := req.SortBy
+	if sortBy == "" {
+		sortBy = "attenuation"
+	}
+	if err := utils.SortPONPower(nil, sortBy); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+
+	oltTxPower := utils.DefaultOLTTxPower
+	if req.OLTTxPower != nil {
+		oltTxPower = *req.OLTTxPower
+	}
+
+	// Render commands using the template for the OLT
+	dev := h.device(req.Host, req.Vendor, req.Model, req.Firmware)
+	templateName, err := h.templateFor("check-pon-power", dev)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
+	}
+	commands, _, err := h.templateMgr.RenderTemplate(templateName, map[string]any{
+		"Board": req.Board,
+		"Pon":   req.PON,
+	})
+	if err != nil {
+		return h.templateErrorResponse(c, err)
+	}
+
+	if req.RenderOnly {
+		return c.JSON(h.createAPIResponse(true, PONPowerResponse{
+			Host:       req.Host,
+			Mode:       "pon-power",
+			Template:   templateName,
+			Commands:   commands,
+			RenderOnly: true,
+			Success:    true,
+		}, ""))
+	}
+
+	// Execute commands on OLT
+	oltReq := olt.OLTRequest{
+		Host:     req.Host,
+		Port:     req.Port,
+		User:     req.User,
+		Password: req.Password,
+		Vendor:   dev.Vendor,
+		Commands: commands,
+	}
+
+	ctx := c.Context()
+	result, err := h.oltService.ExecuteCommands(ctx, oltReq)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+
+	response := PONPowerResponse{
+		Host:     result.Host,
+		Mode:     "pon-power",
+		Template: templateName,
+		Commands: commands,
+		Success:  result.Success,
+		Error:    result.Error,
+		Time:     result.Time,
+	}
+	if response.Success {
+		if cmdErr := commandError(dev.Vendor, result.Output); cmdErr != "" {
+			response.Success = false
+			response.Error = cmdErr
+		}
+	}
+
+	// Parse with the vendor driver, already validated by templateFor
+	if response.Success && result.Output != "" {
+		driver, _ := olt.GetDriver(dev.Vendor)
+		parsed := driver.ParsePONPower(req.Host, req.Board, req.PON, oltTxPower, result.Output)
+		profile, thresholds := h.thresholds.For(req.Host, req.Board, req.PON)
+		parsed.Classify(thresholds)
+		_ = utils.SortPONPower(parsed.ONUs, sortBy)
+
+		onus := []PONPowerEntryDTO{}
+		for _, onu := range parsed.ONUs {
+			if req.Status != "" && !strings.EqualFold(onu.Status, req.Status) {
+				continue
+			}
+			onus = append(onus, PONPowerEntryDTO{
+				Interface:   onu.Interface,
+				ONU:         onu.ONU,
+				ONURxPower:  onu.ONURxPower,
+				OLTRxPower:  onu.OLTRxPower,
+				Attenuation: onu.Attenuation,
+				Status:      onu.Status,
+				RxStatus:    onu.RxStatus,
+			})
+		}
+
+		response.Data = &PONPowerDTO{
+			Host:         parsed.Host,
+			Board:        parsed.Board,
+			PON:          parsed.PON,
+			OLTTxPower:   parsed.OLTTxPower,
+			TotalCount:   parsed.TotalCount,
+			MatchedCount: len(onus),
+			SortBy:       sortBy,
+			Thresholds:   profile,
+			Summary: PONPowerSummaryDTO{
+				Histogram:      parsed.Summary.Histogram,
+				RxHistogram:    parsed.Summary.RxHistogram,
+				MinONURxPower:  parsed.Summary.MinONURxPower,
+				MaxONURxPower:  parsed.Summary.MaxONURxPower,
+				AvgONURxPower:  parsed.Summary.AvgONURxPower,
+				AvgAttenuation: parsed.Summary.AvgAttenuation,
+			},
+			ONUs:      onus,
+			RawOutput: parsed.RawOutput,
+		}
+	}
+
+	return c.JSON(h.createAPIResponse(true, response, ""))
+}
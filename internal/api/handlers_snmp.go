@@ -1,11 +1,16 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/achyar10/go-zteolt/internal/olt"
+	"github.com/achyar10/go-zteolt/internal/utils"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -17,64 +22,38 @@ func (h *Handlers) GetONUByBoardAndPON(c *fiber.Ctx) error {
 			h.createAPIResponse(false, nil, "Invalid request body"))
 	}
 
-	// Get board_id and pon_id from URL parameters
-	boardID, err := strconv.Atoi(c.Params("board_id"))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "Invalid board_id parameter"))
-	}
-
-	ponID, err := strconv.Atoi(c.Params("pon_id"))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "Invalid pon_id parameter"))
-	}
-
-	// Validate parameters
-	if boardID < 1 || boardID > 2 {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "board_id must be 1 or 2"))
-	}
-
-	if ponID < 1 || ponID > 16 {
+	// Convert API request to SNMP service request
+	snmpReq := h.snmpRequest(req.Host, req.Port, req.SNMPAuth)
+	h.snmpDevice(&snmpReq, req.Vendor)
+	snmpReq.Rack = req.Rack
+	snmpReq.Shelf = req.Shelf
+	snmpReq.Timeout = req.Timeout
+	snmpReq.MaxRepetitions = req.MaxRepetitions
+	if msg := snmpPort(c, &snmpReq); msg != "" {
 		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "pon_id must be between 1 and 16"))
-	}
-
-	// Set default timeout if not specified
-	timeout := 30 // seconds
-	if req.Timeout > 0 {
-		timeout = req.Timeout
+			h.createAPIResponse(false, nil, msg))
 	}
 
 	// Initialize SNMP service with realistic data approach
-	snmpService := olt.NewFinalSNMPService(time.Duration(timeout) * time.Second)
-
-	// Convert API request to SNMP service request
-	snmpReq := olt.SNMPRequest{
-		Host:      req.Host,
-		Port:      req.Port,
-		Community: req.Community,
-		BoardID:   boardID,
-		PONID:     ponID,
-		Timeout:   req.Timeout,
-	}
+	snmpService := newSNMPService(req.Timeout)
 
 	// Execute SNMP query
 	ctx := c.Context()
 	result, err := snmpService.GetONUByBoardAndPON(ctx, snmpReq)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
+		return c.Status(snmpErrorStatus(err)).JSON(
 			h.createAPIResponse(false, nil, fmt.Sprintf("SNMP query failed: %v", err)))
 	}
 
 	// Convert to API response format
+	profile, thresholds := h.thresholds.For(req.Host, snmpReq.BoardID, snmpReq.PONID)
 	apiResponse := SNMPMonitoringResponse{
 		Host:          result.Host,
 		BoardID:       result.BoardID,
 		PONID:         result.PONID,
 		TotalONUs:     result.TotalONUs,
-		ONUs:          convertToAPIONUInfo(result.ONUs),
+		Thresholds:    profile,
+		ONUs:          convertToAPIONUInfo(result.ONUs, thresholds),
 		ExecutionTime: result.ExecutionTime,
 		Timestamp:     result.Timestamp,
 	}
@@ -90,17 +69,15 @@ func (h *Handlers) GetONUDetailsSNMP(c *fiber.Ctx) error {
 			h.createAPIResponse(false, nil, "Invalid request body"))
 	}
 
-	// Get parameters from URL
-	boardID, err := strconv.Atoi(c.Params("board_id"))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "Invalid board_id parameter"))
-	}
-
-	ponID, err := strconv.Atoi(c.Params("pon_id"))
-	if err != nil {
+	// Convert API request to SNMP service request
+	snmpReq := h.snmpRequest(req.Host, req.Port, req.SNMPAuth)
+	h.snmpDevice(&snmpReq, req.Vendor)
+	snmpReq.Rack = req.Rack
+	snmpReq.Shelf = req.Shelf
+	snmpReq.Timeout = req.Timeout
+	if msg := snmpPort(c, &snmpReq); msg != "" {
 		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "Invalid pon_id parameter"))
+			h.createAPIResponse(false, nil, msg))
 	}
 
 	onuID, err := strconv.Atoi(c.Params("onu_id"))
@@ -109,51 +86,25 @@ func (h *Handlers) GetONUDetailsSNMP(c *fiber.Ctx) error {
 			h.createAPIResponse(false, nil, "Invalid onu_id parameter"))
 	}
 
-	// Validate parameters
-	if boardID < 1 || boardID > 2 {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "board_id must be 1 or 2"))
-	}
-
-	if ponID < 1 || ponID > 16 {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "pon_id must be between 1 and 16"))
-	}
-
 	if onuID < 1 || onuID > 128 {
 		return c.Status(fiber.StatusBadRequest).JSON(
 			h.createAPIResponse(false, nil, "onu_id must be between 1 and 128"))
 	}
 
-	// Set default timeout if not specified
-	timeout := 30 // seconds
-	if req.Timeout > 0 {
-		timeout = req.Timeout
-	}
-
 	// Initialize SNMP service with realistic data approach
-	snmpService := olt.NewFinalSNMPService(time.Duration(timeout) * time.Second)
-
-	// Convert API request to SNMP service request
-	snmpReq := olt.SNMPRequest{
-		Host:      req.Host,
-		Port:      req.Port,
-		Community: req.Community,
-		BoardID:   boardID,
-		PONID:     ponID,
-		Timeout:   req.Timeout,
-	}
+	snmpService := newSNMPService(req.Timeout)
 
 	// Get specific ONU details directly (much faster - no walk needed)
 	ctx := c.Context()
 	targetONU, err := snmpService.GetONUDetails(ctx, snmpReq, onuID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
+		return c.Status(snmpErrorStatus(err)).JSON(
 			h.createAPIResponse(false, nil, fmt.Sprintf("SNMP query failed: %v", err)))
 	}
 
 	// Convert to API response format
-	apiResponse := convertToAPIONUInfo([]olt.SNMPONUInfo{*targetONU})[0]
+	_, thresholds := h.thresholds.For(req.Host, snmpReq.BoardID, snmpReq.PONID)
+	apiResponse := convertToAPIONUInfo([]olt.SNMPONUInfo{*targetONU}, thresholds)[0]
 
 	return c.JSON(h.createAPIResponse(true, apiResponse, ""))
 }
@@ -166,54 +117,26 @@ func (h *Handlers) GetEmptySlotsSNMP(c *fiber.Ctx) error {
 			h.createAPIResponse(false, nil, "Invalid request body"))
 	}
 
-	// Get parameters from URL
-	boardID, err := strconv.Atoi(c.Params("board_id"))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "Invalid board_id parameter"))
-	}
-
-	ponID, err := strconv.Atoi(c.Params("pon_id"))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "Invalid pon_id parameter"))
-	}
-
-	// Validate parameters
-	if boardID < 1 || boardID > 2 {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "board_id must be 1 or 2"))
-	}
-
-	if ponID < 1 || ponID > 16 {
+	// Convert API request to SNMP service request
+	snmpReq := h.snmpRequest(req.Host, req.Port, req.SNMPAuth)
+	h.snmpDevice(&snmpReq, req.Vendor)
+	snmpReq.Rack = req.Rack
+	snmpReq.Shelf = req.Shelf
+	snmpReq.Timeout = req.Timeout
+	snmpReq.MaxRepetitions = req.MaxRepetitions
+	if msg := snmpPort(c, &snmpReq); msg != "" {
 		return c.Status(fiber.StatusBadRequest).JSON(
-			h.createAPIResponse(false, nil, "pon_id must be between 1 and 16"))
-	}
-
-	// Set default timeout if not specified
-	timeout := 30 // seconds
-	if req.Timeout > 0 {
-		timeout = req.Timeout
+			h.createAPIResponse(false, nil, msg))
 	}
 
 	// Initialize SNMP service with realistic data approach
-	snmpService := olt.NewFinalSNMPService(time.Duration(timeout) * time.Second)
-
-	// Convert API request to SNMP service request
-	snmpReq := olt.SNMPRequest{
-		Host:      req.Host,
-		Port:      req.Port,
-		Community: req.Community,
-		BoardID:   boardID,
-		PONID:     ponID,
-		Timeout:   req.Timeout,
-	}
+	snmpService := newSNMPService(req.Timeout)
 
 	// Get all ONUs for the board/PON
 	ctx := c.Context()
 	result, err := snmpService.GetONUByBoardAndPON(ctx, snmpReq)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
+		return c.Status(snmpErrorStatus(err)).JSON(
 			h.createAPIResponse(false, nil, fmt.Sprintf("SNMP query failed: %v", err)))
 	}
 
@@ -228,8 +151,8 @@ func (h *Handlers) GetEmptySlotsSNMP(c *fiber.Ctx) error {
 	for i := 1; i <= 128; i++ {
 		if !usedONUIDs[i] {
 			emptySlots = append(emptySlots, SNMPEmptySlot{
-				Board: boardID,
-				PON:   ponID,
+				Board: snmpReq.BoardID,
+				PON:   snmpReq.PONID,
 				ONUID: i,
 			})
 		}
@@ -238,8 +161,8 @@ func (h *Handlers) GetEmptySlotsSNMP(c *fiber.Ctx) error {
 	// Convert to API response format
 	apiResponse := SNMPEmptySlotsResponse{
 		Host:          req.Host,
-		BoardID:       boardID,
-		PONID:         ponID,
+		BoardID:       snmpReq.BoardID,
+		PONID:         snmpReq.PONID,
 		TotalEmpty:    len(emptySlots),
 		EmptySlots:    emptySlots,
 		ExecutionTime: result.ExecutionTime,
@@ -249,8 +172,665 @@ func (h *Handlers) GetEmptySlotsSNMP(c *fiber.Ctx) error {
 	return c.JSON(h.createAPIResponse(true, apiResponse, ""))
 }
 
-// Helper function to convert SNMP service ONU info to API model
-func convertToAPIONUInfo(serviceONUs []olt.SNMPONUInfo) []SNMPONUInfo {
+// GetOLTInventorySNMP handles SNMP requests for the ONUs of every active PON
+// port of an OLT
+func (h *Handlers) GetOLTInventorySNMP(c *fiber.Ctx) error {
+	req, snmpReq, msg := h.inventoryRequest(c)
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, msg))
+	}
+
+	snmpService := newSNMPService(req.Timeout)
+
+	ctx := c.Context()
+	inventory, err := snmpService.GetOLTInventory(ctx, snmpReq, req.Concurrency)
+	if err != nil {
+		return c.Status(snmpErrorStatus(err)).JSON(
+			h.createAPIResponse(false, nil, fmt.Sprintf("SNMP query failed: %v", err)))
+	}
+
+	// Convert to API response format
+	apiResponse := SNMPInventoryResponse{
+		Host:          inventory.Host,
+		OIDProfile:    inventory.OIDProfile,
+		TotalPorts:    inventory.TotalPorts,
+		FailedPorts:   inventory.FailedPorts,
+		TotalONUs:     inventory.TotalONUs,
+		Online:        inventory.Online,
+		Offline:       inventory.Offline,
+		ByStatus:      inventory.ByStatus,
+		Ports:         make([]SNMPPortInventory, 0, len(inventory.Ports)),
+		ExecutionTime: inventory.ExecutionTime,
+		Timestamp:     inventory.Timestamp,
+	}
+	for _, port := range inventory.Ports {
+		profile, thresholds := h.thresholds.For(req.Host, port.Slot, port.Port)
+		apiPort := SNMPPortInventory{
+			Rack:          port.Rack,
+			Shelf:         port.Shelf,
+			Slot:          port.Slot,
+			Port:          port.Port,
+			Interface:     port.Interface,
+			TotalONUs:     port.TotalONUs,
+			Online:        port.Online,
+			Offline:       port.Offline,
+			Thresholds:    profile,
+			ExecutionTime: port.ExecutionTime,
+			Error:         port.Error,
+		}
+		if !req.SummaryOnly {
+			apiPort.ONUs = convertToAPIONUInfo(port.ONUs, thresholds)
+		}
+		apiResponse.Ports = append(apiResponse.Ports, apiPort)
+	}
+
+	return c.JSON(h.createAPIResponse(true, apiResponse, ""))
+}
+
+// GetOfflineReportSNMP handles SNMP requests for the offline ONUs of a whole
+// OLT grouped by their last offline reason
+func (h *Handlers) GetOfflineReportSNMP(c *fiber.Ctx) error {
+	req, snmpReq, msg := h.inventoryRequest(c)
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, msg))
+	}
+
+	snmpService := newSNMPService(req.Timeout)
+
+	ctx := c.Context()
+	inventory, err := snmpService.GetOfflineInventory(ctx, snmpReq, req.Concurrency)
+	if err != nil {
+		return c.Status(snmpErrorStatus(err)).JSON(
+			h.createAPIResponse(false, nil, fmt.Sprintf("SNMP query failed: %v", err)))
+	}
+
+	apiResponse := SNMPOfflineReportResponse{
+		Host:          inventory.Host,
+		OIDProfile:    inventory.OIDProfile,
+		TotalPorts:    inventory.TotalPorts,
+		FailedPorts:   inventory.FailedPorts,
+		TotalONUs:     inventory.TotalONUs,
+		Offline:       inventory.Offline,
+		ByReason:      make(map[string]int),
+		Groups:        []SNMPOfflineGroup{},
+		Transitional:  []SNMPOfflineGroup{},
+		ExecutionTime: inventory.ExecutionTime,
+		Timestamp:     inventory.Timestamp,
+	}
+
+	// Group offline ONUs by the last offline reason; ONUs the OLT reports
+	// none for are grouped by their status (e.g. LOS). ONUs coming up are
+	// grouped by their status apart.
+	groups := make(map[string]*SNMPOfflineGroup)
+	transitional := make(map[string]*SNMPOfflineGroup)
+	add := func(groups map[string]*SNMPOfflineGroup, reason string, onu SNMPONUInfo) {
+		group := groups[reason]
+		if group == nil {
+			group = &SNMPOfflineGroup{Reason: reason}
+			groups[reason] = group
+		}
+		group.Count++
+		if !req.SummaryOnly {
+			group.ONUs = append(group.ONUs, onu)
+		}
+	}
+	for _, port := range inventory.Ports {
+		_, thresholds := h.thresholds.For(req.Host, port.Slot, port.Port)
+		for _, onu := range convertToAPIONUInfo(port.ONUs, thresholds) {
+			switch {
+			case onu.Status == "Online":
+				continue
+			case olt.IsOfflineStatus(onu.Status):
+				reason := onu.LastOfflineReason
+				if reason == "" {
+					reason = onu.Status
+				}
+				add(groups, reason, onu)
+			default:
+				add(transitional, onu.Status, onu)
+			}
+		}
+	}
+	for reason, group := range groups {
+		apiResponse.ByReason[reason] = group.Count
+		apiResponse.Groups = append(apiResponse.Groups, *group)
+	}
+	for _, group := range transitional {
+		apiResponse.Transitional = append(apiResponse.Transitional, *group)
+	}
+	for _, list := range [][]SNMPOfflineGroup{apiResponse.Groups, apiResponse.Transitional} {
+		sort.Slice(list, func(i, j int) bool {
+			a, b := list[i], list[j]
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			return a.Reason < b.Reason
+		})
+	}
+
+	return c.JSON(h.createAPIResponse(true, apiResponse, ""))
+}
+
+// inventoryRequest parses and validates a whole-OLT inventory request,
+// returning the SNMP service request or the reason it is invalid
+func (h *Handlers) inventoryRequest(c *fiber.Ctx) (SNMPInventoryRequest, olt.SNMPRequest, string) {
+	var req SNMPInventoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, olt.SNMPRequest{}, "Invalid request body"
+	}
+
+	switch {
+	case req.Concurrency < 0 || req.Concurrency > olt.MaxInventoryConcurrency:
+		return req, olt.SNMPRequest{}, fmt.Sprintf("concurrency must be between 1 and %d, or 0 for the default", olt.MaxInventoryConcurrency)
+	case req.MaxRepetitions < 0 || req.MaxRepetitions > 1000:
+		return req, olt.SNMPRequest{}, "max_repetitions must be between 1 and 1000, or 0 for the default"
+	}
+
+	snmpReq := h.snmpRequest(req.Host, req.Port, req.SNMPAuth)
+	h.snmpDevice(&snmpReq, req.Vendor)
+	snmpReq.Timeout = req.Timeout
+	snmpReq.MaxRepetitions = req.MaxRepetitions
+	return req, snmpReq, ""
+}
+
+// GetPONTrafficSNMP handles SNMP requests for the traffic of the ONUs on a PON port
+func (h *Handlers) GetPONTrafficSNMP(c *fiber.Ctx) error {
+	req, snmpReq, msg := h.trafficRequest(c)
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, msg))
+	}
+
+	snmpService := newSNMPService(req.Timeout)
+
+	ctx := c.Context()
+	result, err := snmpService.GetPONTraffic(ctx, snmpReq, time.Duration(req.SampleInterval)*time.Second)
+	if err != nil {
+		return c.Status(snmpErrorStatus(err)).JSON(
+			h.createAPIResponse(false, nil, fmt.Sprintf("SNMP query failed: %v", err)))
+	}
+
+	onus := make([]SNMPONUTraffic, 0, len(result.ONUs))
+	for _, onu := range result.ONUs {
+		onus = append(onus, convertToAPIONUTraffic(onu))
+	}
+	switch req.SortBy {
+	case "downstream":
+		sort.SliceStable(onus, func(i, j int) bool { return onus[i].DownstreamBps > onus[j].DownstreamBps })
+	case "upstream":
+		sort.SliceStable(onus, func(i, j int) bool { return onus[i].UpstreamBps > onus[j].UpstreamBps })
+	}
+	if req.Top > 0 && req.Top < len(onus) {
+		onus = onus[:req.Top]
+	}
+
+	apiResponse := SNMPPONTrafficResponse{
+		Host:                  result.Host,
+		BoardID:               result.BoardID,
+		PONID:                 result.PONID,
+		Interface:             result.Interface,
+		Technology:            result.Technology,
+		TotalONUs:             result.TotalONUs,
+		UpstreamBps:           math.Round(result.UpstreamBps),
+		DownstreamBps:         math.Round(result.DownstreamBps),
+		UpstreamCapacityBps:   result.UpstreamCapacityBps,
+		DownstreamCapacityBps: result.DownstreamCapacityBps,
+		UpstreamUtilization:   utilization(result.UpstreamBps, result.UpstreamCapacityBps),
+		DownstreamUtilization: utilization(result.DownstreamBps, result.DownstreamCapacityBps),
+		ONUs:                  onus,
+		ExecutionTime:         result.ExecutionTime,
+		Timestamp:             result.Timestamp,
+	}
+
+	return c.JSON(h.createAPIResponse(true, apiResponse, ""))
+}
+
+// GetONUTrafficSNMP handles SNMP requests for the traffic of one ONU
+func (h *Handlers) GetONUTrafficSNMP(c *fiber.Ctx) error {
+	req, snmpReq, msg := h.trafficRequest(c)
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, msg))
+	}
+
+	onuID, err := strconv.Atoi(c.Params("onu_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid onu_id parameter"))
+	}
+
+	if onuID < 1 || onuID > 128 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "onu_id must be between 1 and 128"))
+	}
+
+	snmpService := newSNMPService(req.Timeout)
+
+	ctx := c.Context()
+	onu, err := snmpService.GetONUTraffic(ctx, snmpReq, onuID, time.Duration(req.SampleInterval)*time.Second)
+	if err != nil {
+		return c.Status(snmpErrorStatus(err)).JSON(
+			h.createAPIResponse(false, nil, fmt.Sprintf("SNMP query failed: %v", err)))
+	}
+
+	return c.JSON(h.createAPIResponse(true, convertToAPIONUTraffic(*onu), ""))
+}
+
+// trafficRequest parses and validates a traffic request, returning the SNMP
+// service request or the reason it is invalid
+func (h *Handlers) trafficRequest(c *fiber.Ctx) (SNMPTrafficRequest, olt.SNMPRequest, string) {
+	var req SNMPTrafficRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, olt.SNMPRequest{}, "Invalid request body"
+	}
+
+	snmpReq := h.snmpRequest(req.Host, req.Port, req.SNMPAuth)
+	h.snmpDevice(&snmpReq, req.Vendor)
+	snmpReq.Rack = req.Rack
+	snmpReq.Shelf = req.Shelf
+	snmpReq.Timeout = req.Timeout
+	snmpReq.MaxRepetitions = req.MaxRepetitions
+	if msg := snmpPort(c, &snmpReq); msg != "" {
+		return req, olt.SNMPRequest{}, msg
+	}
+
+	switch {
+	case req.SampleInterval < 0 || time.Duration(req.SampleInterval)*time.Second > olt.MaxTrafficSampleInterval:
+		return req, olt.SNMPRequest{}, fmt.Sprintf("sample_interval must be between 0 and %.0f seconds", olt.MaxTrafficSampleInterval.Seconds())
+	case req.SortBy != "" && req.SortBy != "onu" && req.SortBy != "downstream" && req.SortBy != "upstream":
+		return req, olt.SNMPRequest{}, "sort_by must be onu, downstream or upstream"
+	case req.Top < 0:
+		return req, olt.SNMPRequest{}, "top must not be negative"
+	}
+	return req, snmpReq, ""
+}
+
+// snmpPort sets the board and PON port of an SNMP request from the board_id
+// and pon_id URL parameters and validates them together with its rack, shelf
+// and max-repetitions, returning the reason the request is invalid
+func snmpPort(c *fiber.Ctx, req *olt.SNMPRequest) string {
+	boardID, err := strconv.Atoi(c.Params("board_id"))
+	if err != nil {
+		return "Invalid board_id parameter"
+	}
+
+	ponID, err := strconv.Atoi(c.Params("pon_id"))
+	if err != nil {
+		return "Invalid pon_id parameter"
+	}
+
+	switch {
+	case boardID < 1 || boardID > 255:
+		return "board_id must be between 1 and 255"
+	case req.Rack < 0 || req.Rack > 15 || req.Shelf < 0 || req.Shelf > 255:
+		return "rack must be between 1 and 15 and shelf between 1 and 255, or 0 for the default"
+	case ponID < 1 || ponID > 16:
+		return "pon_id must be between 1 and 16"
+	case req.MaxRepetitions < 0 || req.MaxRepetitions > 1000:
+		return "max_repetitions must be between 1 and 1000, or 0 for the default"
+	}
+
+	req.BoardID = boardID
+	req.PONID = ponID
+	return ""
+}
+
+// convertToAPIONUTraffic converts the traffic of an ONU to the API model
+func convertToAPIONUTraffic(onu olt.ONUTraffic) SNMPONUTraffic {
+	apiONU := SNMPONUTraffic{
+		Board:            onu.Board,
+		PON:              onu.PON,
+		ID:               onu.ID,
+		Name:             onu.Name,
+		UpstreamOctets:   onu.UpstreamOctets,
+		DownstreamOctets: onu.DownstreamOctets,
+		UpstreamBps:      math.Round(onu.UpstreamBps),
+		DownstreamBps:    math.Round(onu.DownstreamBps),
+		RateAvailable:    onu.RateAvailable,
+		Interval:         math.Round(onu.Interval.Seconds()*10) / 10,
+	}
+	for _, gem := range onu.Gemports {
+		apiONU.Gemports = append(apiONU.Gemports, SNMPGemportTraffic{
+			Gemport:          gem.Gemport,
+			UpstreamOctets:   gem.UpstreamOctets,
+			DownstreamOctets: gem.DownstreamOctets,
+			UpstreamBps:      math.Round(gem.UpstreamBps),
+			DownstreamBps:    math.Round(gem.DownstreamBps),
+		})
+	}
+	return apiONU
+}
+
+// utilization returns a rate as a percentage of a capacity, to two decimals
+func utilization(bps, capacity float64) float64 {
+	if capacity <= 0 {
+		return 0
+	}
+	return math.Round(bps/capacity*10000) / 100
+}
+
+// GetPONDiagnosticsSNMP handles SNMP requests for the optical module
+// diagnostics of a PON port
+func (h *Handlers) GetPONDiagnosticsSNMP(c *fiber.Ctx) error {
+	var req SNMPDiagnosticsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid request body"))
+	}
+
+	// Convert API request to SNMP service request
+	snmpReq := h.snmpRequest(req.Host, req.Port, req.SNMPAuth)
+	h.snmpDevice(&snmpReq, req.Vendor)
+	snmpReq.Rack = req.Rack
+	snmpReq.Shelf = req.Shelf
+	snmpReq.Timeout = req.Timeout
+	snmpReq.MaxRepetitions = req.MaxRepetitions
+	if msg := snmpPort(c, &snmpReq); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, msg))
+	}
+
+	snmpService := newSNMPService(req.Timeout)
+
+	ctx := c.Context()
+	diagnostics, err := snmpService.GetPONDiagnostics(ctx, snmpReq)
+	if err != nil {
+		return c.Status(snmpErrorStatus(err)).JSON(
+			h.createAPIResponse(false, nil, fmt.Sprintf("SNMP query failed: %v", err)))
+	}
+
+	return c.JSON(h.createAPIResponse(true, h.convertToAPIDiagnostics(req.Host, *diagnostics), ""))
+}
+
+// GetOLTDiagnosticsSNMP handles SNMP requests for the optical module
+// diagnostics of every PON port of an OLT
+func (h *Handlers) GetOLTDiagnosticsSNMP(c *fiber.Ctx) error {
+	var req SNMPDiagnosticsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid request body"))
+	}
+
+	if req.Concurrency < 0 || req.Concurrency > olt.MaxInventoryConcurrency {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, fmt.Sprintf("concurrency must be between 1 and %d, or 0 for the default", olt.MaxInventoryConcurrency)))
+	}
+
+	if req.MaxRepetitions < 0 || req.MaxRepetitions > 1000 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "max_repetitions must be between 1 and 1000, or 0 for the default"))
+	}
+
+	switch req.Health {
+	case "", "normal", "warning", "critical", "down", "disabled", "unknown":
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "health must be normal, warning, critical, down, disabled or unknown"))
+	}
+
+	snmpService := newSNMPService(req.Timeout)
+
+	// Convert API request to SNMP service request
+	snmpReq := h.snmpRequest(req.Host, req.Port, req.SNMPAuth)
+	h.snmpDevice(&snmpReq, req.Vendor)
+	snmpReq.Timeout = req.Timeout
+	snmpReq.MaxRepetitions = req.MaxRepetitions
+
+	ctx := c.Context()
+	result, err := snmpService.GetOLTDiagnostics(ctx, snmpReq, req.Concurrency)
+	if err != nil {
+		return c.Status(snmpErrorStatus(err)).JSON(
+			h.createAPIResponse(false, nil, fmt.Sprintf("SNMP query failed: %v", err)))
+	}
+
+	apiResponse := SNMPOLTDiagnosticsResponse{
+		Host:          result.Host,
+		TotalPorts:    result.TotalPorts,
+		FailedPorts:   result.FailedPorts,
+		ByHealth:      make(map[string]int),
+		Ports:         make([]SNMPPONDiagnostics, 0, len(result.Ports)),
+		ExecutionTime: result.ExecutionTime,
+		Timestamp:     result.Timestamp,
+	}
+	for _, port := range result.Ports {
+		apiPort := h.convertToAPIDiagnostics(req.Host, port)
+		apiResponse.ByHealth[apiPort.Health]++
+		if req.Health == "" || apiPort.Health == req.Health {
+			apiResponse.Ports = append(apiResponse.Ports, apiPort)
+		}
+	}
+
+	return c.JSON(h.createAPIResponse(true, apiResponse, ""))
+}
+
+// GetOLTSystemSNMP handles SNMP requests for the system, card, fan and power
+// supply health of an OLT
+func (h *Handlers) GetOLTSystemSNMP(c *fiber.Ctx) error {
+	var req SNMPSystemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid request body"))
+	}
+
+	if req.MaxRepetitions < 0 || req.MaxRepetitions > 1000 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "max_repetitions must be between 1 and 1000, or 0 for the default"))
+	}
+
+	snmpService := newSNMPService(req.Timeout)
+
+	// Convert API request to SNMP service request
+	snmpReq := h.snmpRequest(req.Host, req.Port, req.SNMPAuth)
+	h.snmpDevice(&snmpReq, req.Vendor)
+	snmpReq.Timeout = req.Timeout
+	snmpReq.MaxRepetitions = req.MaxRepetitions
+
+	ctx := c.Context()
+	result, err := snmpService.GetSystemHealth(ctx, snmpReq)
+	if err != nil {
+		return c.Status(snmpErrorStatus(err)).JSON(
+			h.createAPIResponse(false, nil, fmt.Sprintf("SNMP query failed: %v", err)))
+	}
+
+	apiResponse := SNMPSystemHealthResponse{
+		Host:          result.Host,
+		Name:          result.Name,
+		Description:   result.Description,
+		ObjectID:      result.ObjectID,
+		Uptime:        result.Uptime.String(),
+		UptimeSeconds: int64(result.Uptime.Seconds()),
+		OIDProfile:    result.OIDProfile,
+		Health:        result.Health,
+		Problems:      append([]string{}, result.Problems...),
+		Cards:         make([]SNMPCardHealth, 0, len(result.Cards)),
+		Fans:          convertToAPIUnits(result.Fans),
+		PowerSupplies: convertToAPIUnits(result.PowerSupplies),
+		ExecutionTime: result.ExecutionTime,
+		Timestamp:     result.Timestamp,
+	}
+	for _, card := range result.Cards {
+		apiResponse.Cards = append(apiResponse.Cards, SNMPCardHealth{
+			Rack:            card.Rack,
+			Shelf:           card.Shelf,
+			Slot:            card.Slot,
+			Type:            card.Type,
+			Status:          card.Status,
+			SoftwareVersion: card.SoftwareVersion,
+			CPUUsage:        card.CPUUsage,
+			MemoryUsage:     card.MemoryUsage,
+			Temperature:     card.Temperature,
+			Health:          card.Health,
+		})
+	}
+
+	return c.JSON(h.createAPIResponse(true, apiResponse, ""))
+}
+
+// convertToAPIUnits converts fans or power supplies to the API model
+func convertToAPIUnits(units []olt.UnitHealth) []SNMPUnitHealth {
+	apiUnits := make([]SNMPUnitHealth, 0, len(units))
+	for _, unit := range units {
+		apiUnits = append(apiUnits, SNMPUnitHealth{
+			Index:  unit.Index,
+			Status: unit.Status,
+			Health: unit.Health,
+		})
+	}
+	return apiUnits
+}
+
+// convertToAPIDiagnostics converts the diagnostics of a PON port to the API
+// model, classifying the readings with the SFP thresholds of the port
+func (h *Handlers) convertToAPIDiagnostics(host string, d olt.PONDiagnostics) SNMPPONDiagnostics {
+	profile, thresholds := h.thresholds.For(host, d.Slot, d.Port)
+	apiPort := SNMPPONDiagnostics{
+		Rack:        d.Rack,
+		Shelf:       d.Shelf,
+		Slot:        d.Slot,
+		Port:        d.Port,
+		Interface:   d.Interface,
+		AdminStatus: d.AdminStatus,
+		OperStatus:  d.OperStatus,
+		TotalONUs:   d.TotalONUs,
+		TxPower:     d.TxPower,
+		Temperature: d.Temperature,
+		BiasCurrent: d.BiasCurrent,
+		Voltage:     d.Voltage,
+		Thresholds:  profile,
+		Error:       d.Error,
+	}
+
+	sfp := thresholds.SFP
+	var statuses []string
+	for _, r := range []struct {
+		value  *float64
+		bands  utils.RangeThresholds
+		status *string
+	}{
+		{d.TxPower, sfp.TxPower, &apiPort.TxPowerStatus},
+		{d.Temperature, sfp.Temperature, &apiPort.TemperatureStatus},
+		{d.BiasCurrent, sfp.BiasCurrent, &apiPort.BiasCurrentStatus},
+		{d.Voltage, sfp.Voltage, &apiPort.VoltageStatus},
+	} {
+		if r.value != nil {
+			*r.status = r.bands.Status(*r.value)
+			statuses = append(statuses, *r.status)
+		}
+	}
+	apiPort.Health = portHealth(d, statuses)
+	return apiPort
+}
+
+// portHealth rates a PON port: disabled when shut down, down when not
+// operational, otherwise the worst status of its readings. Ports that could
+// not be read or report no readings are unknown.
+func portHealth(d olt.PONDiagnostics, statuses []string) string {
+	switch {
+	case d.Error != "":
+		return "unknown"
+	case d.AdminStatus == "down":
+		return "disabled"
+	case d.OperStatus != "" && d.OperStatus != "up":
+		return "down"
+	case len(statuses) == 0:
+		return "unknown"
+	}
+
+	health := "normal"
+	for _, status := range statuses {
+		if status == "critical" {
+			return "critical"
+		}
+		if status == "warning" {
+			health = "warning"
+		}
+	}
+	return health
+}
+
+// newSNMPService returns the SNMP service for a request timeout in seconds,
+// 30 seconds when none is given
+func newSNMPService(timeout int) *olt.SNMPService {
+	if timeout <= 0 {
+		timeout = 30
+	}
+	return olt.NewFinalSNMPService(time.Duration(timeout) * time.Second)
+}
+
+// snmpDevice sets the vendor, model, firmware, OID profile and time zone of
+// an SNMP request from the device registry
+func (h *Handlers) snmpDevice(req *olt.SNMPRequest, vendor string) {
+	dev := h.device(req.Host, vendor, "", "")
+	req.Vendor = dev.Vendor
+	req.Model = dev.Model
+	req.Firmware = dev.Firmware
+	req.OIDProfile = dev.OIDProfile
+	req.TimeZone = dev.TimeZone
+}
+
+// snmpRequest builds the SNMP service request for an OLT. Requests naming no
+// SNMP version or credentials use the SNMP settings of the device registry.
+func (h *Handlers) snmpRequest(host string, port int, auth SNMPAuth) olt.SNMPRequest {
+	req := olt.SNMPRequest{Host: host, Port: port}
+
+	if auth.Version == "" && auth.Community == "" && auth.V3 == nil {
+		if d, ok := h.devices.Get(host); ok && d.SNMP != nil {
+			req.Version = d.SNMP.Version
+			req.Community = d.SNMP.Community
+			if d.SNMP.Username != "" {
+				req.V3 = &olt.SNMPv3Credentials{
+					Username:      d.SNMP.Username,
+					SecurityLevel: d.SNMP.SecurityLevel,
+					AuthProtocol:  d.SNMP.AuthProtocol,
+					AuthPassword:  d.SNMP.AuthPassword,
+					PrivProtocol:  d.SNMP.PrivProtocol,
+					PrivPassword:  d.SNMP.PrivPassword,
+					ContextName:   d.SNMP.ContextName,
+				}
+			}
+		}
+		return req
+	}
+
+	req.Version = auth.Version
+	req.Community = auth.Community
+	if auth.V3 != nil {
+		if req.Version == "" {
+			req.Version = "3"
+		}
+		req.V3 = &olt.SNMPv3Credentials{
+			Username:      auth.V3.Username,
+			SecurityLevel: auth.V3.SecurityLevel,
+			AuthProtocol:  auth.V3.AuthProtocol,
+			AuthPassword:  auth.V3.AuthPassword,
+			PrivProtocol:  auth.V3.PrivProtocol,
+			PrivPassword:  auth.V3.PrivPassword,
+			ContextName:   auth.V3.ContextName,
+		}
+	}
+	return req
+}
+
+// snmpErrorStatus maps SNMP errors to HTTP status codes: bad settings are the
+// client's fault, rejected credentials are an authentication failure and a
+// PON port or ONU missing from the OLT is not found
+func snmpErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, olt.ErrSNMPConfig):
+		return fiber.StatusBadRequest
+	case errors.Is(err, olt.ErrSNMPAuth):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, olt.ErrPONNotFound), errors.Is(err, olt.ErrONUNotFound):
+		return fiber.StatusNotFound
+	}
+	return fiber.StatusInternalServerError
+}
+
+// Helper function to convert SNMP service ONU info to API model, classifying
+// the rx power with the given thresholds
+func convertToAPIONUInfo(serviceONUs []olt.SNMPONUInfo, thresholds utils.OpticalThresholds) []SNMPONUInfo {
 	var apiONUs []SNMPONUInfo
 	for _, onu := range serviceONUs {
 		apiONU := SNMPONUInfo{
@@ -267,8 +847,69 @@ func convertToAPIONUInfo(serviceONUs []olt.SNMPONUInfo) []SNMPONUInfo {
 			IPAddress:    onu.IPAddress,
 			LastOnline:   onu.LastOnline,
 			Uptime:       onu.Uptime,
+
+			LastOffline:          onu.LastOffline,
+			LastOfflineReason:    onu.LastOfflineReason,
+			OpticalDistance:      onu.GponOpticalDistance,
+			StateDuration:        onu.StateDuration,
+			StateDurationSeconds: onu.StateDurationSeconds,
+		}
+		if rx, err := strconv.ParseFloat(onu.RXPower, 64); err == nil {
+			apiONU.RxStatus = thresholds.RxPowerStatus(rx)
 		}
 		apiONUs = append(apiONUs, apiONU)
 	}
 	return apiONUs
 }
+
+// ListTrapEvents handles requests for the recent events decoded from SNMP
+// traps, newest first, filtered by host, type, board, pon, onu and since
+func (h *Handlers) ListTrapEvents(c *fiber.Ctx) error {
+	if h.traps == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(
+			h.createAPIResponse(false, nil, "SNMP trap receiver is not enabled (start the server with -traps)"))
+	}
+
+	filter := olt.TrapEventFilter{
+		Host:  c.Query("host"),
+		Type:  olt.TrapEventType(c.Query("type")),
+		Board: c.QueryInt("board"),
+		PON:   c.QueryInt("pon"),
+		ONU:   c.QueryInt("onu"),
+		Limit: c.QueryInt("limit", 100),
+	}
+	if filter.Type != "" && !slices.Contains(olt.TrapEventTypes, filter.Type) {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, fmt.Sprintf("unknown event type %q", filter.Type)))
+	}
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				h.createAPIResponse(false, nil, "since must be an RFC 3339 time"))
+		}
+		filter.Since = t
+	}
+
+	events := h.traps.Events(filter)
+	byType := make(map[string]int)
+	for _, e := range events {
+		byType[string(e.Type)]++
+	}
+
+	data := map[string]any{
+		"events":  events,
+		"total":   len(events),
+		"by_type": byType,
+	}
+	return c.JSON(h.createAPIResponse(true, data, ""))
+}
+
+// GetTrapStats handles requests for the SNMP trap receiver and sink counters
+func (h *Handlers) GetTrapStats(c *fiber.Ctx) error {
+	if h.traps == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(
+			h.createAPIResponse(false, nil, "SNMP trap receiver is not enabled (start the server with -traps)"))
+	}
+	return c.JSON(h.createAPIResponse(true, h.traps.Stats(), ""))
+}
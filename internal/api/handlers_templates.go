@@ -0,0 +1,280 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/achyar10/go-zteolt/internal/config"
+	"github.com/achyar10/go-zteolt/internal/olt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExecuteTemplate handles rendering a template with arbitrary parameters and
+// executing the resulting commands on the OLT
+func (h *Handlers) ExecuteTemplate(c *fiber.Ctx) error {
+	return h.handleTemplateRequest(c, false)
+}
+
+// RenderTemplate handles rendering a template with arbitrary parameters without executing it
+func (h *Handlers) RenderTemplate(c *fiber.Ctx) error {
+	return h.handleTemplateRequest(c, true)
+}
+
+// handleTemplateRequest renders the :name template and optionally executes it
+func (h *Handlers) handleTemplateRequest(c *fiber.Ctx, renderOnly bool) error {
+	var req ExecuteTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid request body"))
+	}
+	renderOnly = renderOnly || req.RenderOnly
+
+	dev := h.device(req.Host, req.Vendor, req.Model, req.Firmware)
+	name, err := h.templateFor(templateNameParam(c), dev)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
+	}
+	if _, err := h.templateMgr.GetTemplate(name); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+
+	if !renderOnly {
+		if missing := missingConnectionFields(req.Host, req.Port, req.User, req.Password); len(missing) > 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				h.createAPIResponse(false, missing, "Missing connection fields: "+strings.Join(missing, ", ")))
+		}
+	}
+
+	commands, _, err := h.templateMgr.RenderTemplate(name, req.Params)
+	if err != nil {
+		return h.templateErrorResponse(c, err)
+	}
+
+	response := TemplateExecuteResponse{
+		Host:       req.Host,
+		Template:   name,
+		Params:     h.redactParams(name, req.Params),
+		Commands:   commands,
+		RenderOnly: renderOnly,
+		Success:    true,
+	}
+
+	if renderOnly {
+		return c.JSON(h.createAPIResponse(true, response, ""))
+	}
+
+	result, err := h.executeCommands(c.Context(), olt.OLTRequest{
+		Host:     req.Host,
+		Port:     req.Port,
+		User:     req.User,
+		Password: req.Password,
+		Vendor:   dev.Vendor,
+		Commands: commands,
+	}, req.Timeout)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+
+	response.Host = result.Host
+	response.Output = result.Output
+	response.Success = result.Success
+	response.Error = result.Error
+	response.Time = result.Time
+	if response.Success {
+		if cmdErr := commandError(dev.Vendor, result.Output); cmdErr != "" {
+			response.Success = false
+			response.Error = cmdErr
+		}
+	}
+
+	return c.JSON(h.createAPIResponse(true, response, ""))
+}
+
+// executeCommands runs commands on the OLT, using a custom timeout in seconds when given
+func (h *Handlers) executeCommands(ctx context.Context, req olt.OLTRequest, timeout int) (*olt.OLTResponse, error) {
+	if timeout > 0 {
+		return h.oltService.ExecuteCommandsWithCustomTimeout(ctx, req, time.Duration(timeout)*time.Second)
+	}
+	return h.oltService.ExecuteCommands(ctx, req)
+}
+
+// redactParams masks the parameters the template schema marks as secret
+func (h *Handlers) redactParams(name string, params map[string]any) map[string]any {
+	schema, err := h.templateMgr.GetSchema(name)
+	if err != nil {
+		return nil
+	}
+	return schema.Redact(params)
+}
+
+// missingConnectionFields lists the OLT connection fields that are not set
+func missingConnectionFields(host string, port int, user, password string) []string {
+	var missing []string
+	if strings.TrimSpace(host) == "" {
+		missing = append(missing, "host")
+	}
+	if port <= 0 {
+		missing = append(missing, "port")
+	}
+	if strings.TrimSpace(user) == "" {
+		missing = append(missing, "user")
+	}
+	if password == "" {
+		missing = append(missing, "password")
+	}
+	return missing
+}
+
+// CreateTemplate handles creating a new template
+func (h *Handlers) CreateTemplate(c *fiber.Ctx) error {
+	var req TemplateWriteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid request body"))
+	}
+
+	rev, err := h.templateMgr.CreateTemplate(req.Name, req.Content, templateAuthor(c, req.Author), req.Message)
+	if err != nil {
+		return h.templateStoreErrorResponse(c, err)
+	}
+
+	rev.Content = ""
+	return c.Status(fiber.StatusCreated).JSON(h.createAPIResponse(true, rev, ""))
+}
+
+// UpdateTemplate handles replacing the content of a template
+func (h *Handlers) UpdateTemplate(c *fiber.Ctx) error {
+	var req TemplateWriteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid request body"))
+	}
+
+	rev, err := h.templateMgr.UpdateTemplate(templateNameParam(c), req.Content, templateAuthor(c, req.Author), req.Message)
+	if err != nil {
+		return h.templateStoreErrorResponse(c, err)
+	}
+
+	rev.Content = ""
+	return c.JSON(h.createAPIResponse(true, rev, ""))
+}
+
+// DeleteTemplate handles deleting a template from the template directory
+func (h *Handlers) DeleteTemplate(c *fiber.Ctx) error {
+	rev, err := h.templateMgr.DeleteTemplate(templateNameParam(c), templateAuthor(c, c.Query("author")), c.Query("message"))
+	if err != nil {
+		return h.templateStoreErrorResponse(c, err)
+	}
+
+	return c.JSON(h.createAPIResponse(true, rev, ""))
+}
+
+// ListTemplateRevisions handles listing the revision history of a template
+func (h *Handlers) ListTemplateRevisions(c *fiber.Ctx) error {
+	name := templateNameParam(c)
+	revisions, err := h.templateMgr.ListRevisions(name)
+	if err != nil {
+		return h.templateStoreErrorResponse(c, err)
+	}
+
+	data := map[string]any{
+		"name":      name,
+		"revisions": revisions,
+		"count":     len(revisions),
+	}
+	return c.JSON(h.createAPIResponse(true, data, ""))
+}
+
+// GetTemplateRevision handles retrieving a single template revision with its content
+func (h *Handlers) GetTemplateRevision(c *fiber.Ctx) error {
+	revision, err := strconv.Atoi(c.Params("revision"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid revision parameter"))
+	}
+
+	rev, err := h.templateMgr.GetRevision(templateNameParam(c), revision)
+	if err != nil {
+		return h.templateStoreErrorResponse(c, err)
+	}
+
+	return c.JSON(h.createAPIResponse(true, rev, ""))
+}
+
+// DiffTemplateRevisions handles diffing two template revisions (?from=1&to=2, to defaults to latest)
+func (h *Handlers) DiffTemplateRevisions(c *fiber.Ctx) error {
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid from parameter"))
+	}
+	to := c.QueryInt("to", 0)
+
+	name := templateNameParam(c)
+	diff, err := h.templateMgr.DiffRevisions(name, from, to)
+	if err != nil {
+		return h.templateStoreErrorResponse(c, err)
+	}
+
+	if c.Query("format") == "text" {
+		c.Set(fiber.HeaderContentType, "text/x-diff")
+		return c.SendString(diff)
+	}
+
+	data := map[string]any{
+		"name": name,
+		"from": from,
+		"to":   to,
+		"diff": diff,
+	}
+	return c.JSON(h.createAPIResponse(true, data, ""))
+}
+
+// RollbackTemplate handles restoring a previous template revision
+func (h *Handlers) RollbackTemplate(c *fiber.Ctx) error {
+	var req TemplateRollbackRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, nil, "Invalid request body"))
+	}
+
+	rev, err := h.templateMgr.RollbackTemplate(templateNameParam(c), req.Revision, templateAuthor(c, req.Author), req.Message)
+	if err != nil {
+		return h.templateStoreErrorResponse(c, err)
+	}
+
+	rev.Content = ""
+	return c.JSON(h.createAPIResponse(true, rev, ""))
+}
+
+// templateStoreErrorResponse maps template store errors to HTTP status codes
+func (h *Handlers) templateStoreErrorResponse(c *fiber.Ctx, err error) error {
+	var verr *config.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		return c.Status(fiber.StatusBadRequest).JSON(
+			h.createAPIResponse(false, verr.Errors, verr.Error()))
+	case errors.Is(err, config.ErrTemplateNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	case errors.Is(err, config.ErrTemplateExists):
+		return c.Status(fiber.StatusConflict).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			h.createAPIResponse(false, nil, err.Error()))
+	}
+}
+
+// templateAuthor returns the revision author from the request body or the X-Author header
+func templateAuthor(c *fiber.Ctx, author string) string {
+	if author != "" {
+		return author
+	}
+	return c.Get("X-Author")
+}
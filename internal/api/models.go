@@ -6,21 +6,59 @@ import "time"
 
 // AddONURequest represents request to add ONU
 type AddONURequest struct {
-	Host           string `json:"host" binding:"required"`
-	Port           int    `json:"port" binding:"required"`
-	User           string `json:"user" binding:"required"`
-	Password       string `json:"password" binding:"required"`
-	Board          int    `json:"board" binding:"required"`
-	PON            int    `json:"pon" binding:"required"`
-	ONU            int    `json:"onu" binding:"required"`
-	SerialNumber   string `json:"serial_number" binding:"required"`
-	Name           string `json:"name" binding:"required"`
-	SecretPassword string `json:"secret_password" binding:"required"`
-	Description    string `json:"description" binding:"required"`
-	VlanID         int    `json:"vlan_id" binding:"required"`
-	TcontProfile   string `json:"tcont_profile" binding:"required"`
-	TrafficLimit   string `json:"traffic_limit" binding:"required"`
-	RenderOnly     bool   `json:"render_only"`
+	Host           string         `json:"host" binding:"required"`
+	Port           int            `json:"port" binding:"required"`
+	User           string         `json:"user" binding:"required"`
+	Password       string         `json:"password" binding:"required"`
+	Vendor         string         `json:"vendor,omitempty"` // OLT vendor selecting the driver (default: from device registry, then zte)
+	Model          string         `json:"model,omitempty"`  // OLT model selecting the template set (default: from device registry)
+	Firmware       string         `json:"firmware,omitempty"`
+	Board          int            `json:"board" binding:"required"`
+	PON            int            `json:"pon" binding:"required"`
+	ONU            int            `json:"onu" binding:"required"`
+	SerialNumber   string         `json:"serial_number" binding:"required"`
+	Name           string         `json:"name" binding:"required"`
+	SecretPassword string         `json:"secret_password" binding:"required"`
+	Description    string         `json:"description" binding:"required"`
+	VlanID         int            `json:"vlan_id" binding:"required"`
+	TcontProfile   string         `json:"tcont_profile" binding:"required"`
+	TrafficLimit   string         `json:"traffic_limit" binding:"required"`
+	Profile        string         `json:"profile,omitempty"` // service profile name (default: pppoe-router)
+	Params         map[string]any `json:"params,omitempty"`  // extra service profile parameters
+	RenderOnly     bool           `json:"render_only"`
+}
+
+// BulkAddONURequest represents request to provision many ONUs at once
+type BulkAddONURequest struct {
+	Rows       []AddONURequest `json:"rows"`
+	Workers    int             `json:"workers,omitempty"` // max concurrent sessions per OLT (default: OLT parallel workers)
+	RenderOnly bool            `json:"render_only"`
+}
+
+// BulkAddRowResult represents the outcome of a single bulk provisioning row
+type BulkAddRowResult struct {
+	Row          int      `json:"row"`
+	Host         string   `json:"host"`
+	Board        int      `json:"board"`
+	PON          int      `json:"pon"`
+	ONU          int      `json:"onu"`
+	SerialNumber string   `json:"serial_number"`
+	Name         string   `json:"name"`
+	Status       string   `json:"status"` // success, failed, rendered, invalid, skipped
+	Error        string   `json:"error,omitempty"`
+	Commands     []string `json:"commands,omitempty"`
+	Time         string   `json:"execution_time,omitempty"`
+}
+
+// BulkAddONUResponse represents response for bulk ONU provisioning
+type BulkAddONUResponse struct {
+	Total      int                `json:"total"`
+	Succeeded  int                `json:"succeeded"`
+	Failed     int                `json:"failed"`
+	Invalid    int                `json:"invalid"`
+	RenderOnly bool               `json:"render_only"`
+	Results    []BulkAddRowResult `json:"results"`
+	Time       string             `json:"execution_time"`
 }
 
 // DeleteONURequest represents request to delete ONU
@@ -29,6 +67,9 @@ type DeleteONURequest struct {
 	Port       int    `json:"port" binding:"required"`
 	User       string `json:"user" binding:"required"`
 	Password   string `json:"password" binding:"required"`
+	Vendor     string `json:"vendor,omitempty"` // OLT vendor selecting the driver (default: from device registry, then zte)
+	Model      string `json:"model,omitempty"`  // OLT model selecting the template set (default: from device registry)
+	Firmware   string `json:"firmware,omitempty"`
 	Board      int    `json:"board" binding:"required"`
 	PON        int    `json:"pon" binding:"required"`
 	ONU        int    `json:"onu" binding:"required"`
@@ -41,6 +82,9 @@ type CheckAttenuationRequest struct {
 	Port       int    `json:"port" binding:"required"`
 	User       string `json:"user" binding:"required"`
 	Password   string `json:"password" binding:"required"`
+	Vendor     string `json:"vendor,omitempty"` // OLT vendor selecting the driver (default: from device registry, then zte)
+	Model      string `json:"model,omitempty"`  // OLT model selecting the template set (default: from device registry)
+	Firmware   string `json:"firmware,omitempty"`
 	Board      int    `json:"board" binding:"required"`
 	PON        int    `json:"pon" binding:"required"`
 	ONU        int    `json:"onu" binding:"required"`
@@ -76,11 +120,24 @@ type CheckAttenuationResponse struct {
 
 // UnconfiguredONU represents parsed ONU unconfigured data
 type UnconfiguredONUDTO struct {
-	OLTIndex     string `json:"olt_index"`
-	Model        string `json:"model"`
-	SerialNumber string `json:"serial_number"`
-	Board        int    `json:"board"`
-	PON          int    `json:"pon"`
+	OLTIndex         string `json:"olt_index"`
+	Technology       string `json:"technology"`
+	Model            string `json:"model"`
+	ModelVendor      string `json:"model_vendor,omitempty"`
+	ModelDescription string `json:"model_description,omitempty"`
+	SerialNumber     string `json:"serial_number,omitempty"`
+	MACAddress       string `json:"mac_address,omitempty"`
+	Password         string `json:"password,omitempty"`
+	LOID             string `json:"loid,omitempty"`
+	LOIDPassword     string `json:"loid_password,omitempty"`
+	State            string `json:"state,omitempty"`
+	Rack             int    `json:"rack"`
+	Shelf            int    `json:"shelf"`
+	Slot             int    `json:"slot"`
+	Port             int    `json:"port"`
+	ONU              int    `json:"onu,omitempty"`
+	Board            int    `json:"board"`
+	PON              int    `json:"pon"`
 }
 
 // UnconfiguredONUListResponse represents response for check unconfigured ONUs
@@ -117,6 +174,8 @@ type AttenuationDataDTO struct {
 	ONUTxPower  float64 `json:"onu_tx_power_dbm"`
 	Attenuation float64 `json:"attenuation_db"`
 	Status      string  `json:"status"`
+	RxStatus    string  `json:"rx_status,omitempty"`
+	Thresholds  string  `json:"threshold_profile"`
 	RawOutput   string  `json:"raw_output,omitempty"`
 }
 
@@ -126,6 +185,9 @@ type CheckUnconfiguredRequest struct {
 	Port       int    `json:"port" binding:"required"`
 	User       string `json:"user" binding:"required"`
 	Password   string `json:"password" binding:"required"`
+	Vendor     string `json:"vendor,omitempty"` // OLT vendor selecting the driver (default: from device registry, then zte)
+	Model      string `json:"model,omitempty"`  // OLT model selecting the template set (default: from device registry)
+	Firmware   string `json:"firmware,omitempty"`
 	RenderOnly bool   `json:"render_only"`
 }
 
@@ -135,6 +197,9 @@ type RebootONURequest struct {
 	Port       int    `json:"port" binding:"required"`
 	User       string `json:"user" binding:"required"`
 	Password   string `json:"password" binding:"required"`
+	Vendor     string `json:"vendor,omitempty"` // OLT vendor selecting the driver (default: from device registry, then zte)
+	Model      string `json:"model,omitempty"`  // OLT model selecting the template set (default: from device registry)
+	Firmware   string `json:"firmware,omitempty"`
 	Board      int    `json:"board" binding:"required"`
 	PON        int    `json:"pon" binding:"required"`
 	ONU        int    `json:"onu" binding:"required"`
@@ -157,21 +222,24 @@ type SaveConfigurationRequest struct {
 	Port       int    `json:"port" binding:"required"`
 	User       string `json:"user" binding:"required"`
 	Password   string `json:"password" binding:"required"`
+	Vendor     string `json:"vendor,omitempty"` // OLT vendor selecting the driver (default: from device registry, then zte)
+	Model      string `json:"model,omitempty"`  // OLT model selecting the template set (default: from device registry)
+	Firmware   string `json:"firmware,omitempty"`
 	Timeout    int    `json:"timeout,omitempty"` // custom timeout in seconds (default: 300s for save operations)
 	RenderOnly bool   `json:"render_only"`
 }
 
 // SaveConfigurationResponse represents response for save configuration
 type SaveConfigurationResponse struct {
-	Host       string `json:"host"`
-	Mode       string `json:"mode"`
-	Success    bool   `json:"success"`
-	Error      string `json:"error,omitempty"`
-	Time       string `json:"execution_time"`
-	RenderOnly bool   `json:"render_only"`
-	Output     string `json:"output,omitempty"`
-	Status     string `json:"status,omitempty"` // success, in_progress, failed, timeout
-	TimeoutUsed int   `json:"timeout_used,omitempty"` // timeout used in seconds (for debugging)
+	Host        string `json:"host"`
+	Mode        string `json:"mode"`
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
+	Time        string `json:"execution_time"`
+	RenderOnly  bool   `json:"render_only"`
+	Output      string `json:"output,omitempty"`
+	Status      string `json:"status,omitempty"`       // success, in_progress, failed, timeout
+	TimeoutUsed int    `json:"timeout_used,omitempty"` // timeout used in seconds (for debugging)
 }
 
 // BatchCommandsRequest represents request for batch commands
@@ -180,22 +248,66 @@ type BatchCommandsRequest struct {
 	Port     int      `json:"port" binding:"required"`
 	User     string   `json:"user" binding:"required"`
 	Password string   `json:"password" binding:"required"`
+	Vendor   string   `json:"vendor,omitempty"` // default: from device registry, then zte
 	Commands []string `json:"commands" binding:"required"`
 }
 
+// ExecuteTemplateRequest represents request to render/execute an arbitrary template
+type ExecuteTemplateRequest struct {
+	Host       string         `json:"host"`
+	Port       int            `json:"port"`
+	User       string         `json:"user"`
+	Password   string         `json:"password"`
+	Vendor     string         `json:"vendor,omitempty"` // OLT vendor selecting the driver (default: from device registry, then zte)
+	Model      string         `json:"model,omitempty"`  // OLT model selecting the template set (default: from device registry)
+	Firmware   string         `json:"firmware,omitempty"`
+	Params     map[string]any `json:"params"`
+	Timeout    int            `json:"timeout,omitempty"` // custom timeout in seconds
+	RenderOnly bool           `json:"render_only"`
+}
+
+// TemplateExecuteResponse represents response for template render/execute
+type TemplateExecuteResponse struct {
+	Host       string         `json:"host"`
+	Template   string         `json:"template"`
+	Params     map[string]any `json:"params,omitempty"` // secret parameters are masked
+	Commands   []string       `json:"commands"`
+	Output     string         `json:"output,omitempty"`
+	Success    bool           `json:"success"`
+	Error      string         `json:"error,omitempty"`
+	Time       string         `json:"execution_time,omitempty"`
+	RenderOnly bool           `json:"render_only"`
+}
+
+// TemplateWriteRequest represents request to create or update a template
+type TemplateWriteRequest struct {
+	Name    string `json:"name,omitempty"` // only used on create
+	Content string `json:"content" binding:"required"`
+	Author  string `json:"author,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// TemplateRollbackRequest represents request to roll a template back to a revision
+type TemplateRollbackRequest struct {
+	Revision int    `json:"revision" binding:"required"`
+	Author   string `json:"author,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
 // APIResponse represents standard API response
 type APIResponse struct {
-	Success   bool        `json:"success"`
-	Data      any        `json:"data,omitempty"`
-	Error     string      `json:"error,omitempty"`
-	Timestamp time.Time   `json:"timestamp"`
-	RequestID string      `json:"request_id,omitempty"`
+	Success   bool      `json:"success"`
+	Data      any       `json:"data,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"request_id,omitempty"`
 }
 
 // ONUCommandResponse represents response for ONU operations
 type ONUCommandResponse struct {
 	Host       string   `json:"host"`
 	Mode       string   `json:"mode"`
+	Template   string   `json:"template,omitempty"`
 	Commands   []string `json:"commands"`
 	Rendered   string   `json:"rendered,omitempty"`
 	Output     string   `json:"output,omitempty"`
@@ -205,6 +317,187 @@ type ONUCommandResponse struct {
 	RenderOnly bool     `json:"render_only"`
 }
 
+// ONUStateRequest represents request to show the state of the ONUs on a PON port or OLT
+type ONUStateRequest struct {
+	Host       string `json:"host" binding:"required"`
+	Port       int    `json:"port" binding:"required"`
+	User       string `json:"user" binding:"required"`
+	Password   string `json:"password" binding:"required"`
+	Vendor     string `json:"vendor,omitempty"` // OLT vendor selecting the driver (default: from device registry, then zte)
+	Model      string `json:"model,omitempty"`  // OLT model selecting the template set (default: from device registry)
+	Firmware   string `json:"firmware,omitempty"`
+	Board      int    `json:"board,omitempty"`       // with pon: one PON port; alone: filter the whole OLT by board
+	PON        int    `json:"pon,omitempty"`         // omit for the whole OLT
+	State      string `json:"state,omitempty"`       // phase state filter, e.g. working, LOS, DyingGasp, OffLine
+	AdminState string `json:"admin_state,omitempty"` // admin state filter, e.g. enable, disable
+	OMCCState  string `json:"omcc_state,omitempty"`  // OMCC state filter, e.g. enable, disable
+	RenderOnly bool   `json:"render_only"`
+}
+
+// ONUStateDTO represents the state of one ONU
+type ONUStateDTO struct {
+	OnuIndex   string `json:"onu_index"`
+	Board      int    `json:"board"`
+	PON        int    `json:"pon"`
+	ONU        int    `json:"onu"`
+	AdminState string `json:"admin_state"`
+	OMCCState  string `json:"omcc_state"`
+	PhaseState string `json:"phase_state"`
+	Channel    string `json:"channel,omitempty"`
+}
+
+// ONUStateListDTO represents the data transfer object for ONU states
+type ONUStateListDTO struct {
+	Host         string         `json:"host"`
+	TotalCount   int            `json:"total_count"`
+	MatchedCount int            `json:"matched_count"`
+	ByPhaseState map[string]int `json:"by_phase_state"`
+	ONUs         []ONUStateDTO  `json:"onus"`
+	RawOutput    string         `json:"raw_output,omitempty"`
+}
+
+// ONUStateResponse represents response for ONU state requests
+type ONUStateResponse struct {
+	Host       string           `json:"host"`
+	Mode       string           `json:"mode"`
+	Template   string           `json:"template,omitempty"`
+	Commands   []string         `json:"commands,omitempty"`
+	Success    bool             `json:"success"`
+	Error      string           `json:"error,omitempty"`
+	Time       string           `json:"execution_time"`
+	RenderOnly bool             `json:"render_only"`
+	Data       *ONUStateListDTO `json:"data,omitempty"`
+}
+
+// ONUDetailRequest represents request to show the detail info of an ONU
+type ONUDetailRequest struct {
+	Host       string `json:"host" binding:"required"`
+	Port       int    `json:"port" binding:"required"`
+	User       string `json:"user" binding:"required"`
+	Password   string `json:"password" binding:"required"`
+	Vendor     string `json:"vendor,omitempty"` // OLT vendor selecting the driver (default: from device registry, then zte)
+	Model      string `json:"model,omitempty"`  // OLT model selecting the template set (default: from device registry)
+	Firmware   string `json:"firmware,omitempty"`
+	Board      int    `json:"board" binding:"required"`
+	PON        int    `json:"pon" binding:"required"`
+	ONU        int    `json:"onu" binding:"required"`
+	RenderOnly bool   `json:"render_only"`
+}
+
+// ONUHistoryEventDTO represents one online/offline session of an ONU
+type ONUHistoryEventDTO struct {
+	Index       int    `json:"index"`
+	OnlineTime  string `json:"online_time"`
+	OfflineTime string `json:"offline_time,omitempty"`
+	Cause       string `json:"cause,omitempty"`
+	Online      bool   `json:"online"`
+}
+
+// ONUDetailDTO represents the data transfer object for ONU detail info
+type ONUDetailDTO struct {
+	Host                  string               `json:"host"`
+	Interface             string               `json:"interface"`
+	Board                 int                  `json:"board"`
+	PON                   int                  `json:"pon"`
+	ONU                   int                  `json:"onu"`
+	Name                  string               `json:"name"`
+	Type                  string               `json:"type"`
+	State                 string               `json:"state"`
+	AdminState            string               `json:"admin_state"`
+	PhaseState            string               `json:"phase_state"`
+	ConfigState           string               `json:"config_state"`
+	AuthMode              string               `json:"auth_mode"`
+	SerialNumber          string               `json:"serial_number"`
+	Password              string               `json:"password,omitempty"`
+	Description           string               `json:"description"`
+	VendorID              string               `json:"vendor_id"`
+	VersionID             string               `json:"version_id"`
+	DistanceM             int                  `json:"distance_m"`
+	OnlineDuration        string               `json:"online_duration"`
+	OnlineDurationSeconds int64                `json:"online_duration_seconds"`
+	History               []ONUHistoryEventDTO `json:"history"`
+	RawOutput             string               `json:"raw_output,omitempty"`
+}
+
+// ONUDetailResponse represents response for ONU detail info requests
+type ONUDetailResponse struct {
+	Host       string        `json:"host"`
+	Mode       string        `json:"mode"`
+	Template   string        `json:"template,omitempty"`
+	Commands   []string      `json:"commands,omitempty"`
+	Success    bool          `json:"success"`
+	Error      string        `json:"error,omitempty"`
+	Time       string        `json:"execution_time"`
+	RenderOnly bool          `json:"render_only"`
+	Data       *ONUDetailDTO `json:"data,omitempty"`
+}
+
+// PONPowerRequest represents request for the optical power of every ONU on a PON port
+type PONPowerRequest struct {
+	Host       string   `json:"host" binding:"required"`
+	Port       int      `json:"port" binding:"required"`
+	User       string   `json:"user" binding:"required"`
+	Password   string   `json:"password" binding:"required"`
+	Vendor     string   `json:"vendor,omitempty"` // OLT vendor selecting the driver (default: from device registry, then zte)
+	Model      string   `json:"model,omitempty"`  // OLT model selecting the template set (default: from device registry)
+	Firmware   string   `json:"firmware,omitempty"`
+	Board      int      `json:"board" binding:"required"`
+	PON        int      `json:"pon" binding:"required"`
+	OLTTxPower *float64 `json:"olt_tx_power_dbm,omitempty"` // used to estimate attenuation (default: 5.0)
+	SortBy     string   `json:"sort_by,omitempty"`          // attenuation (default), onu, onu_rx, olt_rx
+	Status     string   `json:"status,omitempty"`           // only return ONUs with this status
+	RenderOnly bool     `json:"render_only"`
+}
+
+// PONPowerEntryDTO represents the optical levels of one ONU
+type PONPowerEntryDTO struct {
+	Interface   string   `json:"interface"`
+	ONU         int      `json:"onu"`
+	ONURxPower  *float64 `json:"onu_rx_power_dbm"`
+	OLTRxPower  *float64 `json:"olt_rx_power_dbm"`
+	Attenuation *float64 `json:"attenuation_db"`
+	Status      string   `json:"status"`
+	RxStatus    string   `json:"rx_status"`
+}
+
+// PONPowerSummaryDTO summarizes the optical levels of a PON port
+type PONPowerSummaryDTO struct {
+	Histogram      map[string]int `json:"histogram"`
+	RxHistogram    map[string]int `json:"rx_histogram"`
+	MinONURxPower  *float64       `json:"min_onu_rx_power_dbm,omitempty"`
+	MaxONURxPower  *float64       `json:"max_onu_rx_power_dbm,omitempty"`
+	AvgONURxPower  *float64       `json:"avg_onu_rx_power_dbm,omitempty"`
+	AvgAttenuation *float64       `json:"avg_attenuation_db,omitempty"`
+}
+
+// PONPowerDTO represents the data transfer object for a PON optical sweep
+type PONPowerDTO struct {
+	Host         string             `json:"host"`
+	Board        int                `json:"board"`
+	PON          int                `json:"pon"`
+	OLTTxPower   float64            `json:"olt_tx_power_dbm"`
+	TotalCount   int                `json:"total_count"`
+	MatchedCount int                `json:"matched_count"`
+	SortBy       string             `json:"sort_by"`
+	Thresholds   string             `json:"threshold_profile"`
+	Summary      PONPowerSummaryDTO `json:"summary"`
+	ONUs         []PONPowerEntryDTO `json:"onus"`
+	RawOutput    string             `json:"raw_output,omitempty"`
+}
+
+// PONPowerResponse represents response for PON optical sweep requests
+type PONPowerResponse struct {
+	Host       string       `json:"host"`
+	Mode       string       `json:"mode"`
+	Template   string       `json:"template,omitempty"`
+	Commands   []string     `json:"commands,omitempty"`
+	Success    bool         `json:"success"`
+	Error      string       `json:"error,omitempty"`
+	Time       string       `json:"execution_time"`
+	RenderOnly bool         `json:"render_only"`
+	Data       *PONPowerDTO `json:"data,omitempty"`
+}
+
 // HealthCheckResponse represents health check response
 type HealthCheckResponse struct {
 	Status    string            `json:"status"`
@@ -223,12 +516,35 @@ type ErrorResponse struct {
 
 // SNMP Monitoring Request/Response models
 
+// SNMPv3Credentials represents SNMPv3 USM credentials
+type SNMPv3Credentials struct {
+	Username      string `json:"username"`
+	SecurityLevel string `json:"security_level,omitempty"` // noAuthNoPriv, authNoPriv or authPriv (default: from the passwords)
+	AuthProtocol  string `json:"auth_protocol,omitempty"`  // MD5, SHA, SHA224, SHA256, SHA384, SHA512 (default: SHA)
+	AuthPassword  string `json:"auth_password,omitempty"`
+	PrivProtocol  string `json:"priv_protocol,omitempty"` // DES, AES, AES192, AES256, AES192C, AES256C (default: AES)
+	PrivPassword  string `json:"priv_password,omitempty"`
+	ContextName   string `json:"context_name,omitempty"`
+}
+
+// SNMPAuth represents the SNMP version and credentials of a request. When all
+// are omitted the SNMP settings of the device registry are used.
+type SNMPAuth struct {
+	Version   string             `json:"version,omitempty"` // 1, 2c or 3 (default: 2c, or 3 when snmpv3 is set)
+	Community string             `json:"community,omitempty"`
+	V3        *SNMPv3Credentials `json:"snmpv3,omitempty"`
+}
+
 // SNMPMonitoringRequest represents SNMP monitoring request
 type SNMPMonitoringRequest struct {
-	Host      string `json:"host" binding:"required"`
-	Port      int    `json:"port" binding:"required"`
-	Community string `json:"community" binding:"required"`
-	Timeout   int    `json:"timeout,omitempty"` // optional timeout in seconds
+	Host string `json:"host" binding:"required"`
+	Port int    `json:"port" binding:"required"`
+	SNMPAuth
+	Vendor         string `json:"vendor,omitempty"`          // default: from device registry, then zte
+	Rack           int    `json:"rack,omitempty"`            // default: 1
+	Shelf          int    `json:"shelf,omitempty"`           // default: 1
+	Timeout        int    `json:"timeout,omitempty"`         // optional timeout in seconds
+	MaxRepetitions int    `json:"max_repetitions,omitempty"` // GETBULK max-repetitions (default: 50)
 }
 
 // SNMPONUInfo represents ONU information from SNMP
@@ -241,11 +557,18 @@ type SNMPONUInfo struct {
 	SerialNumber string `json:"serial_number"`
 	OnuType      string `json:"onu_type"`
 	RXPower      string `json:"rx_power"`
+	RxStatus     string `json:"rx_status,omitempty"`
 	TXPower      string `json:"tx_power"`
 	Status       string `json:"status"`
 	IPAddress    string `json:"ip_address,omitempty"`
 	LastOnline   string `json:"last_online,omitempty"`
 	Uptime       string `json:"uptime,omitempty"`
+
+	LastOffline          string `json:"last_offline,omitempty"`
+	LastOfflineReason    string `json:"last_offline_reason,omitempty"`
+	OpticalDistance      string `json:"gpon_optical_distance,omitempty"`
+	StateDuration        string `json:"state_duration,omitempty"`         // time online, or offline, since the last state change
+	StateDurationSeconds int64  `json:"state_duration_seconds,omitempty"` // state_duration in seconds
 }
 
 // SNMPMonitoringResponse represents SNMP monitoring response
@@ -254,6 +577,7 @@ type SNMPMonitoringResponse struct {
 	BoardID       int           `json:"board_id"`
 	PONID         int           `json:"pon_id"`
 	TotalONUs     int           `json:"total_onus"`
+	Thresholds    string        `json:"threshold_profile"`
 	ONUs          []SNMPONUInfo `json:"onus"`
 	ExecutionTime string        `json:"execution_time"`
 	Timestamp     time.Time     `json:"timestamp"`
@@ -261,18 +585,250 @@ type SNMPMonitoringResponse struct {
 
 // SNMPONUDetailsRequest represents request for specific ONU details
 type SNMPONUDetailsRequest struct {
-	Host      string `json:"host" binding:"required"`
-	Port      int    `json:"port" binding:"required"`
-	Community string `json:"community" binding:"required"`
-	Timeout   int    `json:"timeout,omitempty"`
+	Host string `json:"host" binding:"required"`
+	Port int    `json:"port" binding:"required"`
+	SNMPAuth
+	Vendor  string `json:"vendor,omitempty"` // default: from device registry, then zte
+	Rack    int    `json:"rack,omitempty"`   // default: 1
+	Shelf   int    `json:"shelf,omitempty"`  // default: 1
+	Timeout int    `json:"timeout,omitempty"`
 }
 
 // SNMPEmptySlotsRequest represents request for empty ONU boards
 type SNMPEmptySlotsRequest struct {
-	Host      string `json:"host" binding:"required"`
-	Port      int    `json:"port" binding:"required"`
-	Community string `json:"community" binding:"required"`
-	Timeout   int    `json:"timeout,omitempty"`
+	Host string `json:"host" binding:"required"`
+	Port int    `json:"port" binding:"required"`
+	SNMPAuth
+	Vendor         string `json:"vendor,omitempty"` // default: from device registry, then zte
+	Rack           int    `json:"rack,omitempty"`   // default: 1
+	Shelf          int    `json:"shelf,omitempty"`  // default: 1
+	Timeout        int    `json:"timeout,omitempty"`
+	MaxRepetitions int    `json:"max_repetitions,omitempty"` // GETBULK max-repetitions (default: 50)
+}
+
+// SNMPInventoryRequest represents request for the ONUs of a whole OLT
+type SNMPInventoryRequest struct {
+	Host string `json:"host" binding:"required"`
+	Port int    `json:"port" binding:"required"`
+	SNMPAuth
+	Vendor         string `json:"vendor,omitempty"`       // default: from device registry, then zte
+	Concurrency    int    `json:"concurrency,omitempty"`  // PON ports read at once (default: 4, max: 16)
+	SummaryOnly    bool   `json:"summary_only,omitempty"` // omit the ONU lists
+	Timeout        int    `json:"timeout,omitempty"`
+	MaxRepetitions int    `json:"max_repetitions,omitempty"` // GETBULK max-repetitions (default: 50)
+}
+
+// SNMPPortInventory represents the ONUs of one PON port
+type SNMPPortInventory struct {
+	Rack          int           `json:"rack"`
+	Shelf         int           `json:"shelf"`
+	Slot          int           `json:"slot"`
+	Port          int           `json:"port"`
+	Interface     string        `json:"interface"`
+	TotalONUs     int           `json:"total_onus"`
+	Online        int           `json:"online"`
+	Offline       int           `json:"offline"`
+	Thresholds    string        `json:"threshold_profile"`
+	ONUs          []SNMPONUInfo `json:"onus,omitempty"`
+	ExecutionTime string        `json:"execution_time,omitempty"`
+	Error         string        `json:"error,omitempty"`
+}
+
+// SNMPInventoryResponse represents the ONUs of every active PON port of an OLT
+type SNMPInventoryResponse struct {
+	Host          string              `json:"host"`
+	OIDProfile    string              `json:"oid_profile"`
+	TotalPorts    int                 `json:"total_ports"`
+	FailedPorts   int                 `json:"failed_ports"`
+	TotalONUs     int                 `json:"total_onus"`
+	Online        int                 `json:"online"`
+	Offline       int                 `json:"offline"`
+	ByStatus      map[string]int      `json:"by_status"`
+	Ports         []SNMPPortInventory `json:"ports"`
+	ExecutionTime string              `json:"execution_time"`
+	Timestamp     time.Time           `json:"timestamp"`
+}
+
+// SNMPOfflineGroup represents the offline ONUs sharing a last offline reason
+type SNMPOfflineGroup struct {
+	Reason string        `json:"reason"`
+	Count  int           `json:"count"`
+	ONUs   []SNMPONUInfo `json:"onus,omitempty"`
+}
+
+// SNMPOfflineReportResponse represents the offline ONUs of an OLT grouped by
+// their last offline reason, largest group first, and the ONUs coming up
+// grouped by their status
+type SNMPOfflineReportResponse struct {
+	Host          string             `json:"host"`
+	OIDProfile    string             `json:"oid_profile"`
+	TotalPorts    int                `json:"total_ports"`
+	FailedPorts   int                `json:"failed_ports"`
+	TotalONUs     int                `json:"total_onus"`
+	Offline       int                `json:"offline"`
+	ByReason      map[string]int     `json:"by_reason"`
+	Groups        []SNMPOfflineGroup `json:"groups"`
+	Transitional  []SNMPOfflineGroup `json:"transitional"` // ONUs coming up (Logging, Synchronization) by status
+	ExecutionTime string             `json:"execution_time"`
+	Timestamp     time.Time          `json:"timestamp"`
+}
+
+// SNMPTrafficRequest represents request for ONU traffic counters and rates
+type SNMPTrafficRequest struct {
+	Host string `json:"host" binding:"required"`
+	Port int    `json:"port" binding:"required"`
+	SNMPAuth
+	Vendor         string `json:"vendor,omitempty"` // default: from device registry, then zte
+	Rack           int    `json:"rack,omitempty"`   // default: 1
+	Shelf          int    `json:"shelf,omitempty"`  // default: 1
+	Timeout        int    `json:"timeout,omitempty"`
+	MaxRepetitions int    `json:"max_repetitions,omitempty"` // GETBULK max-repetitions (default: 50)
+	SampleInterval int    `json:"sample_interval,omitempty"` // seconds between two counter reads (max 60); 0 rates against the previous poll
+	SortBy         string `json:"sort_by,omitempty"`         // onu (default), downstream, upstream
+	Top            int    `json:"top,omitempty"`             // keep the first N ONUs after sorting
+}
+
+// SNMPGemportTraffic represents the traffic of one gemport of an ONU
+type SNMPGemportTraffic struct {
+	Gemport          int     `json:"gemport"`
+	UpstreamOctets   uint64  `json:"upstream_octets"`
+	DownstreamOctets uint64  `json:"downstream_octets"`
+	UpstreamBps      float64 `json:"upstream_bps"`
+	DownstreamBps    float64 `json:"downstream_bps"`
+}
+
+// SNMPONUTraffic represents the traffic counters and bit rates of an ONU
+type SNMPONUTraffic struct {
+	Board            int                  `json:"board"`
+	PON              int                  `json:"pon"`
+	ID               int                  `json:"onu_id"`
+	Name             string               `json:"name"`
+	UpstreamOctets   uint64               `json:"upstream_octets"`
+	DownstreamOctets uint64               `json:"downstream_octets"`
+	UpstreamBps      float64              `json:"upstream_bps"`
+	DownstreamBps    float64              `json:"downstream_bps"`
+	RateAvailable    bool                 `json:"rate_available"`
+	Interval         float64              `json:"interval_seconds"`
+	Gemports         []SNMPGemportTraffic `json:"gemports,omitempty"`
+}
+
+// SNMPPONTrafficResponse represents the traffic of the ONUs on a PON port
+type SNMPPONTrafficResponse struct {
+	Host                  string           `json:"host"`
+	BoardID               int              `json:"board_id"`
+	PONID                 int              `json:"pon_id"`
+	Interface             string           `json:"interface"`
+	Technology            string           `json:"technology"`
+	TotalONUs             int              `json:"total_onus"`
+	UpstreamBps           float64          `json:"upstream_bps"`
+	DownstreamBps         float64          `json:"downstream_bps"`
+	UpstreamCapacityBps   float64          `json:"upstream_capacity_bps"`
+	DownstreamCapacityBps float64          `json:"downstream_capacity_bps"`
+	UpstreamUtilization   float64          `json:"upstream_utilization"`   // percent of the line rate
+	DownstreamUtilization float64          `json:"downstream_utilization"` // percent of the line rate
+	ONUs                  []SNMPONUTraffic `json:"onus"`
+	ExecutionTime         string           `json:"execution_time"`
+	Timestamp             time.Time        `json:"timestamp"`
+}
+
+// SNMPDiagnosticsRequest represents request for PON port optical module
+// diagnostics, of one port or of the whole OLT
+type SNMPDiagnosticsRequest struct {
+	Host string `json:"host" binding:"required"`
+	Port int    `json:"port" binding:"required"`
+	SNMPAuth
+	Vendor         string `json:"vendor,omitempty"` // default: from device registry, then zte
+	Rack           int    `json:"rack,omitempty"`   // default: 1
+	Shelf          int    `json:"shelf,omitempty"`  // default: 1
+	Timeout        int    `json:"timeout,omitempty"`
+	MaxRepetitions int    `json:"max_repetitions,omitempty"` // GETBULK max-repetitions (default: 50)
+	Concurrency    int    `json:"concurrency,omitempty"`     // whole OLT: PON ports read at once (default: 4, max: 16)
+	Health         string `json:"health,omitempty"`          // whole OLT: only ports with this health
+}
+
+// SNMPPONDiagnostics represents the optical module readings of a PON port
+// with their status against the SFP thresholds
+type SNMPPONDiagnostics struct {
+	Rack              int      `json:"rack"`
+	Shelf             int      `json:"shelf"`
+	Slot              int      `json:"slot"`
+	Port              int      `json:"port"`
+	Interface         string   `json:"interface"`
+	AdminStatus       string   `json:"admin_status"`
+	OperStatus        string   `json:"oper_status"`
+	TotalONUs         int      `json:"total_onus"`
+	TxPower           *float64 `json:"tx_power_dbm"`
+	TxPowerStatus     string   `json:"tx_power_status,omitempty"`
+	Temperature       *float64 `json:"temperature_c"`
+	TemperatureStatus string   `json:"temperature_status,omitempty"`
+	BiasCurrent       *float64 `json:"bias_current_ma"`
+	BiasCurrentStatus string   `json:"bias_current_status,omitempty"`
+	Voltage           *float64 `json:"voltage_v"`
+	VoltageStatus     string   `json:"voltage_status,omitempty"`
+	Health            string   `json:"health"` // normal, warning, critical, down, disabled or unknown
+	Thresholds        string   `json:"threshold_profile"`
+	Error             string   `json:"error,omitempty"`
+}
+
+// SNMPOLTDiagnosticsResponse represents the optical module readings of every
+// PON port of an OLT
+type SNMPOLTDiagnosticsResponse struct {
+	Host          string               `json:"host"`
+	TotalPorts    int                  `json:"total_ports"`
+	FailedPorts   int                  `json:"failed_ports"`
+	ByHealth      map[string]int       `json:"by_health"`
+	Ports         []SNMPPONDiagnostics `json:"ports"`
+	ExecutionTime string               `json:"execution_time"`
+	Timestamp     time.Time            `json:"timestamp"`
+}
+
+// SNMPSystemRequest represents request for the system and card health of an OLT
+type SNMPSystemRequest struct {
+	Host string `json:"host" binding:"required"`
+	Port int    `json:"port" binding:"required"`
+	SNMPAuth
+	Vendor         string `json:"vendor,omitempty"` // default: from device registry, then zte
+	Timeout        int    `json:"timeout,omitempty"`
+	MaxRepetitions int    `json:"max_repetitions,omitempty"` // GETBULK max-repetitions (default: 50)
+}
+
+// SNMPCardHealth represents a card of an OLT
+type SNMPCardHealth struct {
+	Rack            int      `json:"rack"`
+	Shelf           int      `json:"shelf"`
+	Slot            int      `json:"slot"`
+	Type            string   `json:"type"`
+	Status          string   `json:"status"`
+	SoftwareVersion string   `json:"software_version,omitempty"`
+	CPUUsage        *float64 `json:"cpu_usage_percent"`
+	MemoryUsage     *float64 `json:"memory_usage_percent"`
+	Temperature     *float64 `json:"temperature_c"`
+	Health          string   `json:"health"` // normal, warning, critical, absent or unknown
+}
+
+// SNMPUnitHealth represents a fan or power supply of an OLT
+type SNMPUnitHealth struct {
+	Index  string `json:"index"`
+	Status string `json:"status"`
+	Health string `json:"health"`
+}
+
+// SNMPSystemHealthResponse represents the system and card health of an OLT
+type SNMPSystemHealthResponse struct {
+	Host          string           `json:"host"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description"`
+	ObjectID      string           `json:"object_id"`
+	Uptime        string           `json:"uptime"`
+	UptimeSeconds int64            `json:"uptime_seconds"`
+	OIDProfile    string           `json:"oid_profile"`
+	Health        string           `json:"health"` // worst health of the units: normal, warning, critical or unknown
+	Problems      []string         `json:"problems"`
+	Cards         []SNMPCardHealth `json:"cards"`
+	Fans          []SNMPUnitHealth `json:"fans"`
+	PowerSupplies []SNMPUnitHealth `json:"power_supplies"`
+	ExecutionTime string           `json:"execution_time"`
+	Timestamp     time.Time        `json:"timestamp"`
 }
 
 // SNMPEmptySlot represents an empty ONU board
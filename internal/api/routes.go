@@ -36,13 +36,44 @@ func SetupRoutes(handlers *Handlers) *fiber.App {
 
 	// Template management
 	v1.Get("/templates", handlers.ListTemplates)
+	v1.Post("/templates", handlers.CreateTemplate)
+	v1.Get("/templates/:name", handlers.GetTemplate)
+	v1.Put("/templates/:name", handlers.UpdateTemplate)
+	v1.Delete("/templates/:name", handlers.DeleteTemplate)
+	v1.Get("/templates/:name/revisions", handlers.ListTemplateRevisions)
+	v1.Get("/templates/:name/revisions/:revision", handlers.GetTemplateRevision)
+	v1.Get("/templates/:name/diff", handlers.DiffTemplateRevisions)
+	v1.Post("/templates/:name/rollback", handlers.RollbackTemplate)
+	v1.Post("/templates/:name/execute", handlers.ExecuteTemplate)
+	v1.Post("/templates/:name/render", handlers.RenderTemplate)
+
+	// Service profiles
+	v1.Get("/profiles", handlers.ListProfiles)
+	v1.Get("/profiles/:name", handlers.GetProfile)
+
+	// Device inventory
+	v1.Get("/devices", handlers.ListDevices)
+
+	// Optical threshold profiles
+	v1.Get("/thresholds", handlers.ListThresholds)
+
+	// SNMP OID profiles
+	v1.Get("/snmp/oid-profiles", handlers.ListOIDProfiles)
+
+	// SNMP trap events
+	v1.Get("/snmp/traps/events", handlers.ListTrapEvents)
+	v1.Get("/snmp/traps/stats", handlers.GetTrapStats)
 
 	// ONU operations
 	v1.Post("/onu/add", handlers.AddONU)
+	v1.Post("/onu/bulk-add", handlers.BulkAddONU)
 	v1.Post("/onu/delete", handlers.DeleteONU)
 	v1.Post("/onu/reboot", handlers.RebootONU)
 	v1.Post("/onu/check-attenuation", handlers.CheckAttenuation)
 	v1.Post("/onu/check-unconfigured", handlers.CheckUnconfigured)
+	v1.Post("/onu/state", handlers.ONUState)
+	v1.Post("/onu/detail", handlers.ONUDetail)
+	v1.Post("/pon/power", handlers.PONPower)
 
 	// System operations
 	v1.Post("/system/save-configuration", handlers.SaveConfiguration)
@@ -54,6 +85,13 @@ func SetupRoutes(handlers *Handlers) *fiber.App {
 	v1.Post("/board/:board_id/pon/:pon_id/snmp", handlers.GetONUByBoardAndPON)
 	v1.Post("/board/:board_id/pon/:pon_id/onu/:onu_id/snmp", handlers.GetONUDetailsSNMP)
 	v1.Post("/board/:board_id/pon/:pon_id/empty-slots/snmp", handlers.GetEmptySlotsSNMP)
+	v1.Post("/board/:board_id/pon/:pon_id/traffic/snmp", handlers.GetPONTrafficSNMP)
+	v1.Post("/board/:board_id/pon/:pon_id/onu/:onu_id/traffic/snmp", handlers.GetONUTrafficSNMP)
+	v1.Post("/board/:board_id/pon/:pon_id/diagnostics/snmp", handlers.GetPONDiagnosticsSNMP)
+	v1.Post("/olt/inventory/snmp", handlers.GetOLTInventorySNMP)
+	v1.Post("/olt/offline-report/snmp", handlers.GetOfflineReportSNMP)
+	v1.Post("/olt/diagnostics/snmp", handlers.GetOLTDiagnosticsSNMP)
+	v1.Post("/olt/system/snmp", handlers.GetOLTSystemSNMP)
 
 	return app
 }
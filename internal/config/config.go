@@ -20,6 +20,23 @@ type Config struct {
 		MaxRetries      int           `json:"max_retries"`
 		ParallelWorkers int           `json:"parallel_workers"`
 	} `json:"olt"`
+
+	Templates struct {
+		Dir            string        `json:"dir"`
+		ReloadInterval time.Duration `json:"reload_interval"`
+	} `json:"templates"`
+
+	Devices struct {
+		File string `json:"file"` // JSON inventory of OLT models/firmware keyed by host
+	} `json:"devices"`
+
+	Thresholds struct {
+		File string `json:"file"` // JSON optical threshold profiles assigned per OLT and PON
+	} `json:"thresholds"`
+
+	Traps struct {
+		File string `json:"file"` // JSON SNMP trap receiver settings; empty disables the receiver
+	} `json:"traps"`
 }
 
 // DefaultConfig returns default configuration
@@ -39,5 +56,9 @@ func DefaultConfig() *Config {
 	cfg.OLT.MaxRetries = 2
 	cfg.OLT.ParallelWorkers = 8
 
+	// Template defaults
+	cfg.Templates.Dir = "templates"
+	cfg.Templates.ReloadInterval = 5 * time.Second
+
 	return cfg
-}
\ No newline at end of file
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Device describes a known OLT
+type Device struct {
+	Host     string      `json:"host"`
+	Name     string      `json:"name,omitempty"`
+	Vendor   string      `json:"vendor,omitempty"`    // default: zte
+	Model    string      `json:"model,omitempty"`     // e.g. C300, C320, C600
+	Firmware string      `json:"firmware,omitempty"`  // e.g. V2.1.0
+	SNMP     *DeviceSNMP `json:"snmp,omitempty"`      // used by SNMP requests without credentials
+	TimeZone string      `json:"time_zone,omitempty"` // IANA zone of the OLT clock, e.g. Asia/Jakarta (default: the server's)
+
+	// OIDProfile names the SNMP OID profile of the OLT; by default the
+	// profile matching the vendor, model and firmware is used
+	OIDProfile string `json:"oid_profile,omitempty"`
+}
+
+// DeviceSNMP holds the SNMP settings of an OLT
+type DeviceSNMP struct {
+	Version       string `json:"version,omitempty"` // 1, 2c or 3 (default: 2c)
+	Community     string `json:"community,omitempty"`
+	Username      string `json:"username,omitempty"`
+	SecurityLevel string `json:"security_level,omitempty"` // noAuthNoPriv, authNoPriv or authPriv
+	AuthProtocol  string `json:"auth_protocol,omitempty"`  // MD5, SHA, SHA224, SHA256, SHA384, SHA512
+	AuthPassword  string `json:"auth_password,omitempty"`
+	PrivProtocol  string `json:"priv_protocol,omitempty"` // DES, AES, AES192, AES256, AES192C, AES256C
+	PrivPassword  string `json:"priv_password,omitempty"`
+	ContextName   string `json:"context_name,omitempty"`
+}
+
+// redactedSecret replaces SNMP secrets in device listings
+const redactedSecret = "******"
+
+// Redacted returns a copy of the device with its SNMP community and passwords masked
+func (d Device) Redacted() Device {
+	if d.SNMP == nil {
+		return d
+	}
+	snmp := *d.SNMP
+	for _, secret := range []*string{&snmp.Community, &snmp.AuthPassword, &snmp.PrivPassword} {
+		if *secret != "" {
+			*secret = redactedSecret
+		}
+	}
+	d.SNMP = &snmp
+	return d
+}
+
+// deviceFile is the layout of the device inventory file
+type deviceFile struct {
+	Devices []Device `json:"devices"`
+}
+
+// DeviceRegistry holds the OLT inventory keyed by host
+type DeviceRegistry struct {
+	path string
+
+	mu      sync.RWMutex
+	devices map[string]*Device
+}
+
+// NewDeviceRegistry loads the device inventory from a JSON file. An empty path
+// yields an empty registry, so requests must then name the model themselves.
+func NewDeviceRegistry(path string) (*DeviceRegistry, error) {
+	r := &DeviceRegistry{
+		path:    path,
+		devices: make(map[string]*Device),
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads the device inventory file
+func (r *DeviceRegistry) Reload() error {
+	if r.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to read device file %s: %w", r.path, err)
+	}
+
+	var file deviceFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("invalid device file %s: %w", r.path, err)
+	}
+
+	devices := make(map[string]*Device, len(file.Devices))
+	for i := range file.Devices {
+		d := file.Devices[i]
+		if d.Host == "" {
+			return fmt.Errorf("device %d in %s has no host", i+1, r.path)
+		}
+		if _, dup := devices[d.Host]; dup {
+			return fmt.Errorf("duplicate device %s in %s", d.Host, r.path)
+		}
+		if d.Vendor == "" {
+			d.Vendor = "zte"
+		}
+		if d.TimeZone != "" {
+			if _, err := time.LoadLocation(d.TimeZone); err != nil {
+				return fmt.Errorf("device %s in %s: invalid time zone %q", d.Host, r.path, d.TimeZone)
+			}
+		}
+		if d.SNMP != nil {
+			if err := d.SNMP.validate(); err != nil {
+				return fmt.Errorf("device %s in %s: %w", d.Host, r.path, err)
+			}
+		}
+		devices[d.Host] = &d
+	}
+
+	r.mu.Lock()
+	r.devices = devices
+	r.mu.Unlock()
+	return nil
+}
+
+// Get returns the device registered for host
+func (r *DeviceRegistry) Get(host string) (*Device, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.devices[host]
+	return d, ok
+}
+
+// List returns all devices sorted by host
+func (r *DeviceRegistry) List() []Device {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]Device, 0, len(r.devices))
+	for _, d := range r.devices {
+		list = append(list, *d)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Host < list[j].Host })
+	return list
+}
+
+// validate checks the SNMP version and that it has the credentials it needs;
+// protocols are checked by the SNMP service when connecting
+func (s *DeviceSNMP) validate() error {
+	switch strings.TrimPrefix(strings.ToLower(s.Version), "v") {
+	case "", "1", "2", "2c":
+		if s.Community == "" {
+			return fmt.Errorf("snmp community is required for version 1 and 2c")
+		}
+	case "3":
+		if s.Username == "" {
+			return fmt.Errorf("snmp username is required for version 3")
+		}
+	default:
+		return fmt.Errorf("unsupported snmp version %q", s.Version)
+	}
+	return nil
+}
+
+// modelFamilies maps OLT models to the model whose template set they share
+var modelFamilies = map[string]string{
+	"c320": "c300",
+	"c620": "c600",
+	"c650": "c600",
+}
+
+// NormalizeModel turns a model name such as "ZXA10 C600" into its template
+// directory name ("c600")
+func NormalizeModel(model string) string {
+	m := strings.ToLower(strings.TrimSpace(model))
+	m = strings.TrimPrefix(m, "zxa10")
+	return strings.Join(strings.Fields(m), "")
+}
+
+// normalizeFirmware turns a firmware version into its template directory name
+func normalizeFirmware(firmware string) string {
+	return strings.Join(strings.Fields(strings.ToLower(firmware)), "")
+}
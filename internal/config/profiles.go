@@ -0,0 +1,195 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// profilesDir is the subdirectory of the template directory holding service profiles
+const profilesDir = "profiles"
+
+// DefaultServiceProfile is used when an add ONU request does not name a profile
+const DefaultServiceProfile = "pppoe-router"
+
+// ServiceProfile binds a provisioning template to a set of default parameters
+type ServiceProfile struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Template    string         `json:"template"`
+	Defaults    map[string]any `json:"defaults,omitempty"`
+	Required    []string       `json:"required,omitempty"` // parameters the caller must supply
+	Source      string         `json:"source,omitempty"`
+}
+
+// ProfileRegistry holds the named service profiles
+type ProfileRegistry struct {
+	dir      string
+	fallback fs.FS
+
+	mu       sync.RWMutex
+	profiles map[string]*ServiceProfile
+}
+
+// NewProfileRegistry loads every profiles/*.json from the embedded fallback and
+// then from the template directory, which overrides profiles with the same name
+func NewProfileRegistry(dir string, fallback fs.FS) (*ProfileRegistry, error) {
+	r := &ProfileRegistry{
+		dir:      dir,
+		fallback: fallback,
+		profiles: make(map[string]*ServiceProfile),
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads all profile definitions
+func (r *ProfileRegistry) Reload() error {
+	profiles := make(map[string]*ServiceProfile)
+
+	if r.fallback != nil {
+		if err := loadProfiles(r.fallback, "embedded", profiles); err != nil {
+			return err
+		}
+	}
+	if r.dir != "" {
+		err := loadProfiles(os.DirFS(r.dir), "directory", profiles)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	r.profiles = profiles
+	r.mu.Unlock()
+	return nil
+}
+
+// loadProfiles reads profile JSON files from the profiles directory of fsys into profiles
+func loadProfiles(fsys fs.FS, source string, profiles map[string]*ServiceProfile) error {
+	entries, err := fs.ReadDir(fsys, profilesDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(profilesDir, e.Name()))
+		if err != nil {
+			return err
+		}
+
+		var p ServiceProfile
+		if err := json.Unmarshal(data, &p); err != nil {
+			return fmt.Errorf("invalid profile %s: %w", e.Name(), err)
+		}
+		if p.Name == "" {
+			p.Name = strings.TrimSuffix(e.Name(), ".json")
+		}
+		if p.Template == "" {
+			return fmt.Errorf("profile %s has no template", p.Name)
+		}
+		p.Source = source
+		profiles[p.Name] = &p
+	}
+	return nil
+}
+
+// Validate checks that every profile refers to an existing template and that
+// its defaults and required parameters match the template schema
+func (r *ProfileRegistry) Validate(tm *TemplateManager) error {
+	var errs []string
+	for _, p := range r.List() {
+		schema, err := tm.GetSchema(p.Template)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("profile %s: %v", p.Name, err))
+			continue
+		}
+		if schema == nil {
+			continue
+		}
+		for key, value := range p.Defaults {
+			param := schema.param(key)
+			if param == nil {
+				errs = append(errs, fmt.Sprintf("profile %s: default %s is not a parameter of %s", p.Name, key, p.Template))
+				continue
+			}
+			if _, err := param.coerce(value); err != nil {
+				errs = append(errs, fmt.Sprintf("profile %s: default %s %v", p.Name, key, err))
+			}
+		}
+		for _, name := range p.Required {
+			if schema.param(name) == nil {
+				errs = append(errs, fmt.Sprintf("profile %s: required %s is not a parameter of %s", p.Name, name, p.Template))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Get returns a profile by name
+func (r *ProfileRegistry) Get(name string) (*ServiceProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("service profile %s not found", name)
+	}
+	return p, nil
+}
+
+// List returns all profiles sorted by name
+func (r *ProfileRegistry) List() []ServiceProfile {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]ServiceProfile, 0, len(r.profiles))
+	for _, p := range r.profiles {
+		list = append(list, *p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Resolve merges the profile defaults with the supplied parameters (which take
+// precedence) and checks that all parameters required by the profile are present
+func (p *ServiceProfile) Resolve(params map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(p.Defaults)+len(params))
+	for k, v := range p.Defaults {
+		out[k] = v
+	}
+	for k, v := range params {
+		out[k] = v
+	}
+
+	var missing []string
+	for _, name := range p.Required {
+		if v, ok := params[name]; !ok || v == nil || v == "" || v == 0 {
+			missing = append(missing, name+" is required by profile "+p.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &ValidationError{Template: p.Template, Errors: missing}
+	}
+	return out, nil
+}
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// passwordAlphabet excludes characters that are easily confused or need escaping on the CLI
+const passwordAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// maxVlanRange limits the number of VLANs vlanRange expands to
+const maxVlanRange = 4094
+
+// templateFuncs are the helper functions available to every template
+var templateFuncs = template.FuncMap{
+	"gponOlt":      gponOlt,
+	"gponOnu":      gponOnu,
+	"c600Olt":      c600Olt,
+	"c600Onu":      c600Onu,
+	"vlanRange":    vlanRange,
+	"speedProfile": speedProfile,
+	"safe":         safeName,
+	"quote":        quoteText,
+	"genPassword":  genPassword,
+}
+
+// gponOlt returns the C300/C320 PON port interface name, e.g. gpon-olt_1/2/3
+func gponOlt(board, pon any) (string, error) {
+	b, p, err := toInts(board, pon)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("gpon-olt_1/%d/%d", b, p), nil
+}
+
+// gponOnu returns the C300/C320 ONU interface name, e.g. gpon-onu_1/2/3:4
+func gponOnu(board, pon, onu any) (string, error) {
+	b, p, err := toInts(board, pon)
+	if err != nil {
+		return "", err
+	}
+	o, err := toInt(onu)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("gpon-onu_1/%d/%d:%d", b, p, o), nil
+}
+
+// c600Olt returns the C600/C650 PON port interface name, e.g. gpon_olt-1/2/3
+func c600Olt(board, pon any) (string, error) {
+	b, p, err := toInts(board, pon)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("gpon_olt-1/%d/%d", b, p), nil
+}
+
+// c600Onu returns the C600/C650 ONU interface name, e.g. gpon_onu-1/2/3:4
+func c600Onu(board, pon, onu any) (string, error) {
+	b, p, err := toInts(board, pon)
+	if err != nil {
+		return "", err
+	}
+	o, err := toInt(onu)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("gpon_onu-1/%d/%d:%d", b, p, o), nil
+}
+
+// vlanRange expands a VLAN list such as "100-103,200" into individual VLAN IDs
+func vlanRange(spec any) ([]int, error) {
+	var vlans []int
+	for _, part := range strings.Split(fmt.Sprint(spec), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("invalid VLAN %q", part)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(strings.TrimSpace(to)); err != nil {
+				return nil, fmt.Errorf("invalid VLAN range %q", part)
+			}
+		}
+
+		if start < 1 || end > 4094 || start > end {
+			return nil, fmt.Errorf("invalid VLAN range %q", part)
+		}
+		if len(vlans)+end-start+1 > maxVlanRange {
+			return nil, fmt.Errorf("VLAN list %q is too long", spec)
+		}
+		for v := start; v <= end; v++ {
+			vlans = append(vlans, v)
+		}
+	}
+	return vlans, nil
+}
+
+// speedProfile maps a speed such as "20M", "20mbps", "512k" or "1G" to a
+// profile name with the given prefix, e.g. speedProfile "UP" "20mbps" -> UP-20M
+func speedProfile(prefix string, speed any) (string, error) {
+	s := strings.ToUpper(strings.TrimSpace(fmt.Sprint(speed)))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "BPS"), "B")
+
+	mult := 1.0 // megabits
+	switch {
+	case strings.HasSuffix(s, "K"):
+		mult, s = 1.0/1024, strings.TrimSuffix(s, "K")
+	case strings.HasSuffix(s, "M"):
+		s = strings.TrimSuffix(s, "M")
+	case strings.HasSuffix(s, "G"):
+		mult, s = 1024, strings.TrimSuffix(s, "G")
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || n <= 0 {
+		return "", fmt.Errorf("invalid speed %q", speed)
+	}
+
+	mbps := n * mult
+	var name string
+	switch {
+	case mbps < 1:
+		name = fmt.Sprintf("%dK", int(math.Round(mbps*1024)))
+	case mbps >= 1024 && math.Mod(mbps, 1024) == 0:
+		name = fmt.Sprintf("%dG", int(mbps/1024))
+	default:
+		name = fmt.Sprintf("%dM", int(math.Round(mbps)))
+	}
+
+	if prefix == "" {
+		return name, nil
+	}
+	return prefix + "-" + name, nil
+}
+
+// safeName makes a value usable as a single CLI token: whitespace becomes '_'
+// and anything other than letters, digits and -_.@ is dropped
+func safeName(value any) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(fmt.Sprint(value)) {
+		switch {
+		case unicode.IsSpace(r):
+			b.WriteByte('_')
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.@", r)):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// quoteText wraps free text such as descriptions in double quotes, dropping
+// characters that would break the CLI line (quotes, '?' and control characters)
+func quoteText(value any) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range strings.TrimSpace(fmt.Sprint(value)) {
+		if r == '"' || r == '?' || r >= unicode.MaxASCII || unicode.IsControl(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// genPassword returns a random password of the given length (default 12)
+func genPassword(length ...int) (string, error) {
+	n := 12
+	if len(length) > 0 {
+		n = length[0]
+	}
+	if n < 1 || n > 64 {
+		return "", fmt.Errorf("password length must be between 1 and 64")
+	}
+
+	limit := big.NewInt(int64(len(passwordAlphabet)))
+	out := make([]byte, n)
+	for i := range out {
+		idx, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		out[i] = passwordAlphabet[idx.Int64()]
+	}
+	return string(out), nil
+}
+
+// toInt converts a template value to an int
+func toInt(value any) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v == math.Trunc(v) {
+			return int(v), nil
+		}
+	case string:
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("%v is not an integer", value)
+}
+
+// toInts converts two template values to ints
+func toInts(a, b any) (int, int, error) {
+	x, err := toInt(a)
+	if err != nil {
+		return 0, 0, err
+	}
+	y, err := toInt(b)
+	if err != nil {
+		return 0, 0, err
+	}
+	return x, y, nil
+}
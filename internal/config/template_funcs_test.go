@@ -0,0 +1,202 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInterfaceNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func() (string, error)
+		want    string
+		wantErr bool
+	}{
+		{"gponOlt ints", func() (string, error) { return gponOlt(2, 3) }, "gpon-olt_1/2/3", false},
+		{"gponOlt strings", func() (string, error) { return gponOlt("2", " 3 ") }, "gpon-olt_1/2/3", false},
+		{"gponOlt json numbers", func() (string, error) { return gponOlt(2.0, 16.0) }, "gpon-olt_1/2/16", false},
+		{"gponOlt bad board", func() (string, error) { return gponOlt("x", 3) }, "", true},
+		{"gponOlt fractional pon", func() (string, error) { return gponOlt(2, 3.5) }, "", true},
+		{"gponOlt nil", func() (string, error) { return gponOlt(nil, 3) }, "", true},
+		{"gponOnu ints", func() (string, error) { return gponOnu(2, 3, 4) }, "gpon-onu_1/2/3:4", false},
+		{"gponOnu int64", func() (string, error) { return gponOnu(int64(2), 3, int64(128)) }, "gpon-onu_1/2/3:128", false},
+		{"gponOnu bad onu", func() (string, error) { return gponOnu(2, 3, "4a") }, "", true},
+		{"gponOnu bad pon", func() (string, error) { return gponOnu(2, true, 4) }, "", true},
+		{"c600Olt ints", func() (string, error) { return c600Olt(1, 8) }, "gpon_olt-1/1/8", false},
+		{"c600Olt bad pon", func() (string, error) { return c600Olt(1, "") }, "", true},
+		{"c600Onu ints", func() (string, error) { return c600Onu(1, 8, 12) }, "gpon_onu-1/1/8:12", false},
+		{"c600Onu strings", func() (string, error) { return c600Onu("1", "8", "12") }, "gpon_onu-1/1/8:12", false},
+		{"c600Onu bad onu", func() (string, error) { return c600Onu(1, 8, 1.25) }, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVlanRange(t *testing.T) {
+	tests := []struct {
+		spec    any
+		want    []int
+		wantErr bool
+	}{
+		{"100", []int{100}, false},
+		{100, []int{100}, false},
+		{"100-103,200", []int{100, 101, 102, 103, 200}, false},
+		{" 10 - 12 , , 20 ", []int{10, 11, 12, 20}, false},
+		{"1-4094", nil, false}, // checked by length below
+		{"", nil, false},
+		{"0", nil, true},
+		{"4095", nil, true},
+		{"200-100", nil, true},
+		{"abc", nil, true},
+		{"100-x", nil, true},
+		{"1-4094,5", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := vlanRange(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("vlanRange(%v) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if tt.spec == "1-4094" {
+			if len(got) != 4094 || got[0] != 1 || got[4093] != 4094 {
+				t.Errorf("vlanRange(%v) returned %d VLANs", tt.spec, len(got))
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("vlanRange(%v) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestSpeedProfile(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		speed   any
+		want    string
+		wantErr bool
+	}{
+		{"UP", "20M", "UP-20M", false},
+		{"UP", "20mbps", "UP-20M", false},
+		{"DOWN", "100Mb", "DOWN-100M", false},
+		{"UP", 50, "UP-50M", false},
+		{"UP", "512k", "UP-512K", false},
+		{"UP", "1G", "UP-1G", false},
+		{"UP", "1.5G", "UP-1536M", false},
+		{"UP", "2048M", "UP-2G", false},
+		{"", "10M", "10M", false},
+		{"UP", "0", "", true},
+		{"UP", "-5M", "", true},
+		{"UP", "fast", "", true},
+		{"UP", "", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := speedProfile(tt.prefix, tt.speed)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("speedProfile(%q, %v) error = %v, wantErr %v", tt.prefix, tt.speed, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("speedProfile(%q, %v) = %q, want %q", tt.prefix, tt.speed, got, tt.want)
+		}
+	}
+}
+
+func TestSafeName(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"cust-001", "cust-001"},
+		{"john.doe@isp", "john.doe@isp"},
+		{"  John Doe  ", "John_Doe"},
+		{"a\tb", "a_b"},
+		{"a\nno onu 5", "a_no_onu_5"},
+		{"name;reboot|x", "namerebootx"},
+		{`"quoted"?`, "quoted"},
+		{"café", "caf"},
+		{42, "42"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := safeName(tt.value); got != tt.want {
+			t.Errorf("safeName(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteText(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"Customer A", `"Customer A"`},
+		{"  padded  ", `"padded"`},
+		{`say "hi"`, `"say hi"`},
+		{"what?", `"what"`},
+		{"line\nno onu 5", `"lineno onu 5"`},
+		{"tab\there", `"tabhere"`},
+		{"Jl. Merdeka No. 5, RT 01/02", `"Jl. Merdeka No. 5, RT 01/02"`},
+		{"café", `"caf"`},
+		{123, `"123"`},
+		{"", `""`},
+	}
+
+	for _, tt := range tests {
+		if got := quoteText(tt.value); got != tt.want {
+			t.Errorf("quoteText(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestGenPassword(t *testing.T) {
+	tests := []struct {
+		length  []int
+		want    int
+		wantErr bool
+	}{
+		{nil, 12, false},
+		{[]int{1}, 1, false},
+		{[]int{20}, 20, false},
+		{[]int{64}, 64, false},
+		{[]int{0}, 0, true},
+		{[]int{-1}, 0, true},
+		{[]int{65}, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := genPassword(tt.length...)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("genPassword(%v) error = %v, wantErr %v", tt.length, err, tt.wantErr)
+			continue
+		}
+		if len(got) != tt.want {
+			t.Errorf("genPassword(%v) length = %d, want %d", tt.length, len(got), tt.want)
+		}
+		for _, r := range got {
+			if !strings.ContainsRune(passwordAlphabet, r) {
+				t.Errorf("genPassword(%v) = %q contains %q outside the alphabet", tt.length, got, r)
+			}
+		}
+	}
+
+	a, _ := genPassword(32)
+	b, _ := genPassword(32)
+	if a == b {
+		t.Errorf("genPassword returned the same password twice: %q", a)
+	}
+}
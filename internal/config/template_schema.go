@@ -0,0 +1,327 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// frontMatterDelim delimits the JSON front matter at the top of a template
+const frontMatterDelim = "---"
+
+// TemplateParam describes a single template parameter
+type TemplateParam struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"` // string, int, float, bool
+	Required    bool     `json:"required,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	Min         *float64 `json:"min,omitempty"` // value for numbers, length for strings
+	Max         *float64 `json:"max,omitempty"` // value for numbers, length for strings
+	Default     any      `json:"default,omitempty"`
+	Secret      bool     `json:"secret,omitempty"`
+	Description string   `json:"description,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// TemplateSchema describes the parameters accepted by a template
+type TemplateSchema struct {
+	Description string          `json:"description,omitempty"`
+	Params      []TemplateParam `json:"params"`
+}
+
+// ValidationError is returned when template parameters do not match the schema
+type ValidationError struct {
+	Template string
+	Errors   []string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation failed for template %s: %s", e.Template, strings.Join(e.Errors, "; "))
+}
+
+// splitFrontMatter separates the JSON front matter from the template body
+func splitFrontMatter(content string) (*TemplateSchema, string, error) {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, frontMatterDelim+"\n") {
+		return nil, content, nil
+	}
+
+	rest := normalized[len(frontMatterDelim)+1:]
+	end := strings.Index(rest, "\n"+frontMatterDelim)
+	if end < 0 {
+		return nil, "", fmt.Errorf("unterminated front matter")
+	}
+
+	header := rest[:end]
+	body := strings.TrimPrefix(rest[end+len(frontMatterDelim)+1:], "\n")
+
+	schema := &TemplateSchema{}
+	if err := json.Unmarshal([]byte(header), schema); err != nil {
+		return nil, "", fmt.Errorf("invalid front matter: %w", err)
+	}
+	if err := schema.compile(); err != nil {
+		return nil, "", err
+	}
+
+	return schema, body, nil
+}
+
+// compile validates the schema definition and compiles parameter patterns
+func (s *TemplateSchema) compile() error {
+	seen := make(map[string]bool)
+	for i := range s.Params {
+		p := &s.Params[i]
+		if p.Name == "" {
+			return fmt.Errorf("parameter %d has no name", i+1)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("duplicate parameter %s", p.Name)
+		}
+		seen[p.Name] = true
+
+		if p.Type == "" {
+			p.Type = "string"
+		}
+		switch p.Type {
+		case "string", "int", "float", "bool":
+		default:
+			return fmt.Errorf("parameter %s has unknown type %q", p.Name, p.Type)
+		}
+
+		if p.Pattern != "" {
+			re, err := regexp.Compile("^(?:" + p.Pattern + ")$")
+			if err != nil {
+				return fmt.Errorf("parameter %s has invalid pattern: %w", p.Name, err)
+			}
+			p.re = re
+		}
+
+		if p.Default != nil {
+			value, err := p.coerce(p.Default)
+			if err == nil {
+				err = p.check(value)
+			}
+			if err != nil {
+				return fmt.Errorf("parameter %s has invalid default: %w", p.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Apply validates params against the schema and returns a copy with defaults
+// applied and values converted to their declared types
+func (s *TemplateSchema) Apply(templateName string, params map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(params))
+	for k, v := range params {
+		out[k] = v
+	}
+
+	var errs []string
+	for _, p := range s.Params {
+		value, ok := out[p.Name]
+		if !ok || value == nil || value == "" {
+			if p.Default != nil {
+				value, _ = p.coerce(p.Default)
+				if err := p.check(value); err != nil {
+					errs = append(errs, fmt.Sprintf("%s default %v", p.Name, err))
+					continue
+				}
+				out[p.Name] = value
+				continue
+			}
+			if p.Required {
+				errs = append(errs, p.Name+" is required")
+				continue
+			}
+			// Optional parameters render as their zero value instead of failing missingkey=error
+			out[p.Name] = p.zero()
+			continue
+		}
+
+		converted, err := p.coerce(value)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s %v", p.Name, err))
+			continue
+		}
+		if err := p.check(converted); err != nil {
+			errs = append(errs, fmt.Sprintf("%s %v", p.Name, err))
+			continue
+		}
+		out[p.Name] = converted
+	}
+
+	if len(errs) > 0 {
+		return nil, &ValidationError{Template: templateName, Errors: errs}
+	}
+	return out, nil
+}
+
+// Redact returns a copy of params with secret values masked
+func (s *TemplateSchema) Redact(params map[string]any) map[string]any {
+	out := make(map[string]any, len(params))
+	for k, v := range params {
+		out[k] = v
+	}
+	if s == nil {
+		return out
+	}
+	for _, p := range s.Params {
+		if _, ok := out[p.Name]; ok && p.Secret {
+			out[p.Name] = "******"
+		}
+	}
+	return out
+}
+
+// Public returns a copy of the schema safe to expose through the API
+func (s *TemplateSchema) Public() *TemplateSchema {
+	if s == nil {
+		return nil
+	}
+	out := &TemplateSchema{Description: s.Description, Params: make([]TemplateParam, len(s.Params))}
+	copy(out.Params, s.Params)
+	for i := range out.Params {
+		if out.Params[i].Secret {
+			out.Params[i].Default = nil
+		}
+	}
+	return out
+}
+
+// HasParam reports whether the schema declares a parameter
+func (s *TemplateSchema) HasParam(name string) bool {
+	return s.param(name) != nil
+}
+
+// param returns the schema parameter with the given name
+func (s *TemplateSchema) param(name string) *TemplateParam {
+	for i := range s.Params {
+		if s.Params[i].Name == name {
+			return &s.Params[i]
+		}
+	}
+	return nil
+}
+
+// zero returns the zero value of the parameter type
+func (p *TemplateParam) zero() any {
+	switch p.Type {
+	case "int":
+		return 0
+	case "float":
+		return 0.0
+	case "bool":
+		return false
+	default:
+		return ""
+	}
+}
+
+// coerce converts a JSON/CSV/Go value to the declared parameter type
+func (p *TemplateParam) coerce(value any) (any, error) {
+	switch p.Type {
+	case "int":
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case float64:
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("must be an integer")
+			}
+			return int(v), nil
+		case json.Number:
+			n, err := strconv.Atoi(v.String())
+			if err != nil {
+				return nil, fmt.Errorf("must be an integer")
+			}
+			return n, nil
+		case string:
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("must be an integer")
+			}
+			return n, nil
+		}
+		return nil, fmt.Errorf("must be an integer")
+
+	case "float":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("must be a number")
+			}
+			return f, nil
+		}
+		return nil, fmt.Errorf("must be a number")
+
+	case "bool":
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("must be a boolean")
+			}
+			return b, nil
+		}
+		return nil, fmt.Errorf("must be a boolean")
+
+	default:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case int, int64, float64, bool, json.Number:
+			return fmt.Sprint(v), nil
+		}
+		return nil, fmt.Errorf("must be a string")
+	}
+}
+
+// check validates a converted value against pattern and min/max constraints.
+// Strings never contain line breaks: each rendered line is sent to the OLT as
+// its own command, so a newline in a value would inject another command.
+func (p *TemplateParam) check(value any) error {
+	var n float64
+	switch v := value.(type) {
+	case int:
+		n = float64(v)
+	case float64:
+		n = v
+	case string:
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("must not contain line breaks")
+		}
+		if p.re != nil && !p.re.MatchString(v) {
+			return fmt.Errorf("must match pattern %s", p.Pattern)
+		}
+		n = float64(len(v))
+	default:
+		return nil
+	}
+
+	unit := ""
+	if p.Type == "string" {
+		unit = " characters"
+	}
+	if p.Min != nil && n < *p.Min {
+		return fmt.Errorf("must be at least %v%s", *p.Min, unit)
+	}
+	if p.Max != nil && n > *p.Max {
+		return fmt.Errorf("must be at most %v%s", *p.Max, unit)
+	}
+	return nil
+}
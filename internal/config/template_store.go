@@ -0,0 +1,367 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// revisionsDir is the hidden directory under the template directory holding revision history
+const revisionsDir = ".revisions"
+
+// templateNameRE matches valid template names, optionally nested (e.g. c600/add-onu or c600/v1.2/add-onu)
+var templateNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*(/[a-z0-9][a-z0-9_.-]*)*$`)
+
+// ErrTemplateNotFound is returned when a template or revision does not exist
+var ErrTemplateNotFound = errors.New("template not found")
+
+// ErrTemplateExists is returned when creating a template that already exists
+var ErrTemplateExists = errors.New("template already exists")
+
+// TemplateRevision represents a stored revision of a template
+type TemplateRevision struct {
+	Name      string    `json:"name"`
+	Revision  int       `json:"revision"`
+	Action    string    `json:"action"` // import, create, update, delete, rollback
+	Author    string    `json:"author"`
+	Message   string    `json:"message,omitempty"`
+	Content   string    `json:"content,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateTemplate creates a new template in the template directory
+func (tm *TemplateManager) CreateTemplate(name, content, author, message string) (*TemplateRevision, error) {
+	if err := tm.checkWritable(name); err != nil {
+		return nil, err
+	}
+
+	tm.storeMu.Lock()
+	defer tm.storeMu.Unlock()
+
+	if _, err := os.Stat(tm.templatePath(name)); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateExists, name)
+	}
+
+	return tm.writeTemplate(name, content, "create", author, message)
+}
+
+// UpdateTemplate replaces the content of an existing template. Updating an
+// embedded-only template writes an override to the template directory.
+func (tm *TemplateManager) UpdateTemplate(name, content, author, message string) (*TemplateRevision, error) {
+	if err := tm.checkWritable(name); err != nil {
+		return nil, err
+	}
+
+	tm.mu.RLock()
+	_, exists := tm.templates[name]
+	tm.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	tm.storeMu.Lock()
+	defer tm.storeMu.Unlock()
+
+	return tm.writeTemplate(name, content, "update", author, message)
+}
+
+// DeleteTemplate removes a template file from the template directory. The
+// deletion is recorded in the revision history and the embedded version, if
+// any, becomes active again.
+func (tm *TemplateManager) DeleteTemplate(name, author, message string) (*TemplateRevision, error) {
+	if err := tm.checkWritable(name); err != nil {
+		return nil, err
+	}
+
+	tm.storeMu.Lock()
+	defer tm.storeMu.Unlock()
+
+	p := tm.templatePath(name)
+	if _, err := os.Stat(p); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	if err := tm.importBaseline(name); err != nil {
+		return nil, err
+	}
+
+	if err := os.Remove(p); err != nil {
+		return nil, fmt.Errorf("failed to delete template %s: %w", name, err)
+	}
+
+	rev, err := tm.appendRevision(name, "delete", author, message, "")
+	if err != nil {
+		return nil, err
+	}
+
+	tm.mu.Lock()
+	delete(tm.templates, name)
+	delete(tm.errors, name)
+	delete(tm.failed, name)
+	tm.mu.Unlock()
+	tm.restoreEmbedded(name)
+
+	return rev, nil
+}
+
+// RollbackTemplate makes the content of a previous revision active again by
+// recording it as a new revision
+func (tm *TemplateManager) RollbackTemplate(name string, revision int, author, message string) (*TemplateRevision, error) {
+	if err := tm.checkWritable(name); err != nil {
+		return nil, err
+	}
+
+	target, err := tm.GetRevision(name, revision)
+	if err != nil {
+		return nil, err
+	}
+	if target.Action == "delete" {
+		return nil, fmt.Errorf("revision %d of %s is a deletion and cannot be restored", revision, name)
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("rollback to revision %d", revision)
+	}
+
+	tm.storeMu.Lock()
+	defer tm.storeMu.Unlock()
+
+	return tm.writeTemplate(name, target.Content, "rollback", author, message)
+}
+
+// ListRevisions returns the revision history of a template, newest first, without content
+func (tm *TemplateManager) ListRevisions(name string) ([]TemplateRevision, error) {
+	revisions, err := tm.readRevisions(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("%w: no revisions for %s", ErrTemplateNotFound, name)
+	}
+
+	out := make([]TemplateRevision, len(revisions))
+	for i, rev := range revisions {
+		rev.Content = ""
+		out[len(revisions)-1-i] = rev
+	}
+	return out, nil
+}
+
+// GetRevision returns a single revision of a template including its content
+func (tm *TemplateManager) GetRevision(name string, revision int) (*TemplateRevision, error) {
+	if !templateNameRE.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+
+	data, err := os.ReadFile(tm.revisionPath(name, revision))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s revision %d", ErrTemplateNotFound, name, revision)
+		}
+		return nil, err
+	}
+
+	var rev TemplateRevision
+	if err := json.Unmarshal(data, &rev); err != nil {
+		return nil, fmt.Errorf("corrupt revision %d of %s: %w", revision, name, err)
+	}
+	return &rev, nil
+}
+
+// DiffRevisions returns a unified diff between two revisions of a template.
+// A zero "to" revision compares against the latest revision.
+func (tm *TemplateManager) DiffRevisions(name string, from, to int) (string, error) {
+	if to == 0 {
+		revisions, err := tm.readRevisions(name)
+		if err != nil {
+			return "", err
+		}
+		if len(revisions) == 0 {
+			return "", fmt.Errorf("%w: no revisions for %s", ErrTemplateNotFound, name)
+		}
+		to = revisions[len(revisions)-1].Revision
+	}
+
+	a, err := tm.GetRevision(name, from)
+	if err != nil {
+		return "", err
+	}
+	b, err := tm.GetRevision(name, to)
+	if err != nil {
+		return "", err
+	}
+
+	return unifiedDiff(a.Content, b.Content,
+		fmt.Sprintf("%s@%d", name, from), fmt.Sprintf("%s@%d", name, to)), nil
+}
+
+// ActiveRevision returns the latest revision number of a template, or 0 if it has no history
+func (tm *TemplateManager) ActiveRevision(name string) int {
+	revisions, err := tm.readRevisions(name)
+	if err != nil || len(revisions) == 0 {
+		return 0
+	}
+	return revisions[len(revisions)-1].Revision
+}
+
+// writeTemplate validates content, writes it to the template directory, records
+// the revision and activates it immediately; callers must hold tm.storeMu
+func (tm *TemplateManager) writeTemplate(name, content, action, author, message string) (*TemplateRevision, error) {
+	tmpl, schema, err := parseTemplate(name, content)
+	if err != nil {
+		return nil, &ValidationError{Template: name, Errors: []string{err.Error()}}
+	}
+
+	if err := tm.importBaseline(name); err != nil {
+		return nil, err
+	}
+
+	p := tm.templatePath(name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create template directory: %w", err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write template %s: %w", name, err)
+	}
+
+	rev, err := tm.appendRevision(name, action, author, message, content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Activate the written content directly: a second write within the mod time
+	// granularity of the filesystem would look unchanged to loadFile
+	var modTime time.Time
+	if info, err := os.Stat(p); err == nil {
+		modTime = info.ModTime()
+	}
+	tm.activate(name, p, modTime, tmpl, schema, rev.Revision)
+
+	return rev, nil
+}
+
+// importBaseline records the current file content as the first revision of a
+// template that was created outside the API, so it can be rolled back to;
+// callers must hold tm.storeMu
+func (tm *TemplateManager) importBaseline(name string) error {
+	revisions, err := tm.readRevisions(name)
+	if err != nil || len(revisions) > 0 {
+		return err
+	}
+
+	content, err := os.ReadFile(tm.templatePath(name))
+	if err != nil {
+		return nil
+	}
+
+	_, err = tm.appendRevision(name, "import", "system", "imported from template directory", string(content))
+	return err
+}
+
+// appendRevision stores a new revision with the next revision number; callers
+// must hold tm.storeMu
+func (tm *TemplateManager) appendRevision(name, action, author, message, content string) (*TemplateRevision, error) {
+	revisions, err := tm.readRevisions(name)
+	if err != nil {
+		return nil, err
+	}
+
+	next := 1
+	if len(revisions) > 0 {
+		next = revisions[len(revisions)-1].Revision + 1
+	}
+	if author == "" {
+		author = "unknown"
+	}
+
+	rev := &TemplateRevision{
+		Name:      name,
+		Revision:  next,
+		Action:    action,
+		Author:    author,
+		Message:   message,
+		Content:   content,
+		CreatedAt: time.Now(),
+	}
+
+	data, err := json.MarshalIndent(rev, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	p := tm.revisionPath(name, next)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create revision directory: %w", err)
+	}
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write revision: %w", err)
+	}
+
+	return rev, nil
+}
+
+// readRevisions loads all revisions of a template ordered by revision number
+func (tm *TemplateManager) readRevisions(name string) ([]TemplateRevision, error) {
+	if !templateNameRE.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+
+	dir := filepath.Join(tm.dir, revisionsDir, filepath.FromSlash(name))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var revisions []TemplateRevision
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		rev, err := tm.GetRevision(name, n)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *rev)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	return revisions, nil
+}
+
+// checkWritable verifies the manager has a template directory and the name is valid
+func (tm *TemplateManager) checkWritable(name string) error {
+	if tm.dir == "" {
+		return fmt.Errorf("no template directory configured")
+	}
+	if !templateNameRE.MatchString(name) {
+		return &ValidationError{Template: name, Errors: []string{
+			"name must be lowercase letters, digits, '-', '_' or '.', optionally nested with '/'",
+		}}
+	}
+	return nil
+}
+
+// templatePath returns the file path of a template in the template directory
+func (tm *TemplateManager) templatePath(name string) string {
+	return filepath.Join(tm.dir, filepath.FromSlash(name)+templateExt)
+}
+
+// revisionPath returns the file path of a stored revision
+func (tm *TemplateManager) revisionPath(name string, revision int) string {
+	return filepath.Join(tm.dir, revisionsDir, filepath.FromSlash(name), fmt.Sprintf("%06d.json", revision))
+}
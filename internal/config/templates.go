@@ -2,67 +2,412 @@ package config
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log"
 	"os"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 )
 
+// templateExt is the file extension of command templates
+const templateExt = ".tmpl"
+
 // TemplateManager handles all template operations
 type TemplateManager struct {
-	templates map[string]*template.Template
+	dir      string
+	fallback fs.FS
+
+	mu        sync.RWMutex
+	templates map[string]*templateEntry
+	errors    map[string]string
+	failed    map[string]time.Time // mod time of files that failed to parse
+
+	storeMu sync.Mutex // serializes template writes, their revisions and reloads
+
+	stop chan struct{}
+	once sync.Once
 }
 
-// NewTemplateManager creates a new template manager
-func NewTemplateManager() (*TemplateManager, error) {
+// templateEntry holds a parsed template and where it was loaded from
+type templateEntry struct {
+	tmpl     *template.Template
+	schema   *TemplateSchema
+	source   string
+	path     string
+	modTime  time.Time
+	loadedAt time.Time
+	revision int // latest revision of a directory template, 0 without history
+}
+
+// TemplateInfo describes a loaded template
+type TemplateInfo struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Source      string    `json:"source"` // directory or embedded
+	Path        string    `json:"path"`
+	Revision    int       `json:"revision,omitempty"` // active revision when managed through the API
+	LoadedAt    time.Time `json:"loaded_at"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// TemplateDetail describes a loaded template including its parameter schema
+type TemplateDetail struct {
+	TemplateInfo
+	Schema *TemplateSchema `json:"schema,omitempty"`
+}
+
+// NewTemplateManager creates a new template manager that discovers every *.tmpl
+// under dir, falling back to the templates in fallback for anything not on disk
+func NewTemplateManager(dir string, fallback fs.FS) (*TemplateManager, error) {
 	tm := &TemplateManager{
-		templates: make(map[string]*template.Template),
+		dir:       dir,
+		fallback:  fallback,
+		templates: make(map[string]*templateEntry),
+		errors:    make(map[string]string),
+		failed:    make(map[string]time.Time),
+		stop:      make(chan struct{}),
 	}
 
-	// Load all templates
-	templates := map[string]string{
-		"add-onu":            "templates/add-onu.tmpl",
-		"delete-onu":         "templates/delete-onu.tmpl",
-		"reboot-onu":         "templates/reboot-onu.tmpl",
-		"save-config":        "templates/save-config.tmpl",
-		"check-attenuation":  "templates/check-attenuation.tmpl",
-		"check-unconfigured": "templates/check-unconfigured.tmpl",
+	if fallback != nil {
+		if err := tm.loadEmbedded(); err != nil {
+			return nil, fmt.Errorf("failed to load embedded templates: %w", err)
+		}
 	}
 
-	for name, path := range templates {
-		if err := tm.loadTemplate(name, path); err != nil {
-			return nil, fmt.Errorf("failed to load template %s: %w", name, err)
-		}
+	if err := tm.Reload(); err != nil {
+		return nil, err
+	}
+
+	if len(tm.templates) == 0 {
+		return nil, fmt.Errorf("no templates found in %s", dir)
 	}
 
 	return tm, nil
 }
 
-// loadTemplate loads a template from file
-func (tm *TemplateManager) loadTemplate(name, path string) error {
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return err
+// loadEmbedded parses all templates from the fallback filesystem
+func (tm *TemplateManager) loadEmbedded() error {
+	return fs.WalkDir(tm.fallback, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(p, templateExt) {
+			return nil
+		}
+
+		content, err := fs.ReadFile(tm.fallback, p)
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSuffix(p, templateExt)
+		tmpl, schema, err := parseTemplate(name, string(content))
+		if err != nil {
+			return fmt.Errorf("template %s: %w", name, err)
+		}
+
+		tm.templates[name] = &templateEntry{
+			tmpl:     tmpl,
+			schema:   schema,
+			source:   "embedded",
+			path:     p,
+			loadedAt: time.Now(),
+		}
+		return nil
+	})
+}
+
+// Reload scans the template directory and (re)loads new or changed files.
+// A template that fails to parse keeps its previous version and the error is
+// recorded; a template deleted from disk reverts to its embedded version.
+func (tm *TemplateManager) Reload() error {
+	if tm.dir == "" {
+		return nil
 	}
 
-	tmpl, err := template.New(name).Parse(string(content))
+	// A scan must not load a file half way through an API write
+	tm.storeMu.Lock()
+	defer tm.storeMu.Unlock()
+
+	found := make(map[string]bool)
+	err := filepath.WalkDir(tm.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != tm.dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, templateExt) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(tm.dir, p)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.ToSlash(rel), templateExt)
+		found[name] = true
+
+		info, err := d.Info()
+		if err != nil {
+			tm.setError(name, err)
+			return nil
+		}
+		tm.loadFile(name, p, info.ModTime())
+		return nil
+	})
 	if err != nil {
-		return err
+		if errors.Is(err, fs.ErrNotExist) {
+			if tm.fallback == nil {
+				return fmt.Errorf("template directory %s not found", tm.dir)
+			}
+			log.Printf("⚠️  Template directory %s not found, using embedded templates", tm.dir)
+			return nil
+		}
+		return fmt.Errorf("failed to scan template directory %s: %w", tm.dir, err)
 	}
 
-	tm.templates[name] = tmpl
+	tm.removeMissing(found)
 	return nil
 }
 
-// RenderTemplate renders a template with the given data
-func (tm *TemplateManager) RenderTemplate(templateName string, data interface{}) ([]string, string, error) {
-	tmpl, exists := tm.templates[templateName]
+// loadFile parses a template file if it is new or modified since the last load
+func (tm *TemplateManager) loadFile(name, p string, modTime time.Time) {
+	tm.mu.RLock()
+	current, exists := tm.templates[name]
+	failedMod, failed := tm.failed[name]
+	tm.mu.RUnlock()
+
+	if exists && current.source == "directory" && current.modTime.Equal(modTime) {
+		return
+	}
+	if failed && failedMod.Equal(modTime) {
+		return
+	}
+
+	content, err := os.ReadFile(p)
+	if err != nil {
+		tm.setError(name, err)
+		return
+	}
+
+	tmpl, schema, err := parseTemplate(name, string(content))
+	if err != nil {
+		tm.setError(name, err)
+		// Remember the mod time so the broken file is not re-parsed every scan
+		tm.mu.Lock()
+		tm.failed[name] = modTime
+		tm.mu.Unlock()
+		return
+	}
+
+	tm.activate(name, p, modTime, tmpl, schema, tm.ActiveRevision(name))
+	if exists && current.source == "directory" {
+		log.Printf("🔄 Reloaded template %s", name)
+	}
+}
+
+// activate makes a parsed template file the active version of a template
+func (tm *TemplateManager) activate(name, p string, modTime time.Time, tmpl *template.Template, schema *TemplateSchema, revision int) {
+	tm.mu.Lock()
+	tm.templates[name] = &templateEntry{
+		tmpl:     tmpl,
+		schema:   schema,
+		source:   "directory",
+		path:     p,
+		modTime:  modTime,
+		loadedAt: time.Now(),
+		revision: revision,
+	}
+	delete(tm.errors, name)
+	delete(tm.failed, name)
+	tm.mu.Unlock()
+}
+
+// removeMissing drops directory templates whose files no longer exist
+func (tm *TemplateManager) removeMissing(found map[string]bool) {
+	var reverted []string
+
+	tm.mu.Lock()
+	for name, entry := range tm.templates {
+		if entry.source != "directory" || found[name] {
+			continue
+		}
+		delete(tm.templates, name)
+		delete(tm.errors, name)
+		reverted = append(reverted, name)
+	}
+	for name := range tm.failed {
+		if !found[name] {
+			delete(tm.failed, name)
+			delete(tm.errors, name)
+		}
+	}
+	tm.mu.Unlock()
+
+	for _, name := range reverted {
+		tm.restoreEmbedded(name)
+		log.Printf("🗑️  Template %s removed from %s", name, tm.dir)
+	}
+}
+
+// restoreEmbedded re-registers the embedded version of a template, if any
+func (tm *TemplateManager) restoreEmbedded(name string) {
+	if tm.fallback == nil {
+		return
+	}
+
+	p := path.Clean(name + templateExt)
+	content, err := fs.ReadFile(tm.fallback, p)
+	if err != nil {
+		return
+	}
+
+	tmpl, schema, err := parseTemplate(name, string(content))
+	if err != nil {
+		return
+	}
+
+	tm.mu.Lock()
+	tm.templates[name] = &templateEntry{
+		tmpl:     tmpl,
+		schema:   schema,
+		source:   "embedded",
+		path:     p,
+		loadedAt: time.Now(),
+	}
+	tm.mu.Unlock()
+}
+
+// parseTemplate strips the front matter and parses the template body. Missing
+// keys are rendering errors so an absent parameter never renders as "<no value>".
+func parseTemplate(name, content string) (*template.Template, *TemplateSchema, error) {
+	schema, body, err := splitFrontMatter(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tmpl, schema, nil
+}
+
+// setError records a load error for a template
+func (tm *TemplateManager) setError(name string, err error) {
+	tm.mu.Lock()
+	tm.errors[name] = err.Error()
+	tm.mu.Unlock()
+	log.Printf("⚠️  Failed to load template %s: %v", name, err)
+}
+
+// Watch polls the template directory for changes every interval until Close is called
+func (tm *TemplateManager) Watch(interval time.Duration) {
+	if tm.dir == "" || interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-tm.stop:
+				return
+			case <-ticker.C:
+				if err := tm.Reload(); err != nil {
+					log.Printf("⚠️  Template reload failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Close stops watching the template directory
+func (tm *TemplateManager) Close() {
+	tm.once.Do(func() { close(tm.stop) })
+}
+
+// Resolve returns the variant of a template to use for an OLT model and
+// firmware, trying model/firmware/name, model/name, the model family and
+// finally name itself. A directory in name (e.g. a vendor directory such as
+// huawei/add-onu) is kept in front of the model directories. A model whose
+// template directory lacks the variant is an error rather than a fallback to
+// name, which would send another model's CLI syntax to the OLT.
+func (tm *TemplateManager) Resolve(name, model, firmware string) (string, error) {
+	model = NormalizeModel(model)
+	if model == "" {
+		return name, nil
+	}
+
+	dir, base := path.Split(name)
+	modelDirs := []string{dir + model + "/"}
+	if family, ok := modelFamilies[model]; ok {
+		modelDirs = append(modelDirs, dir+family+"/")
+	}
+
+	var candidates []string
+	if fw := normalizeFirmware(firmware); fw != "" {
+		candidates = append(candidates, modelDirs[0]+fw+"/"+base)
+	}
+	for _, d := range modelDirs {
+		candidates = append(candidates, d+base)
+	}
+
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	for _, candidate := range candidates {
+		if _, ok := tm.templates[candidate]; ok {
+			return candidate, nil
+		}
+	}
+	for _, d := range modelDirs {
+		for loaded := range tm.templates {
+			if strings.HasPrefix(loaded, d) {
+				return "", fmt.Errorf("%w: %s has no variant for model %s (expected %s)", ErrTemplateNotFound, name, model, d+base)
+			}
+		}
+	}
+	return name, nil
+}
+
+// RenderTemplate renders a template with the given parameters, which are
+// first validated against the template schema, if it has one
+func (tm *TemplateManager) RenderTemplate(templateName string, params map[string]any) ([]string, string, error) {
+	tm.mu.RLock()
+	entry, exists := tm.templates[templateName]
+	tm.mu.RUnlock()
 	if !exists {
 		return nil, "", fmt.Errorf("template %s not found", templateName)
 	}
 
+	// Validate parameters and apply defaults from the template schema
+	if params == nil {
+		params = map[string]any{}
+	}
+	if entry.schema != nil {
+		validated, err := entry.schema.Apply(templateName, params)
+		if err != nil {
+			return nil, "", err
+		}
+		params = validated
+	}
+
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
+	if err := entry.tmpl.Execute(&buf, params); err != nil {
 		return nil, "", err
 	}
 
@@ -82,9 +427,91 @@ func (tm *TemplateManager) RenderTemplate(templateName string, data interface{})
 
 // GetAvailableTemplates returns list of available template names
 func (tm *TemplateManager) GetAvailableTemplates() []string {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
 	templates := make([]string, 0, len(tm.templates))
 	for name := range tm.templates {
 		templates = append(templates, name)
 	}
+	sort.Strings(templates)
 	return templates
-}
\ No newline at end of file
+}
+
+// GetTemplateInfo returns details of every loaded template, plus templates that
+// failed to load and have no previous version
+func (tm *TemplateManager) GetTemplateInfo() []TemplateInfo {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	infos := make([]TemplateInfo, 0, len(tm.templates))
+	for name, entry := range tm.templates {
+		infos = append(infos, tm.templateInfo(name, entry))
+	}
+	for name, msg := range tm.errors {
+		if _, ok := tm.templates[name]; !ok {
+			infos = append(infos, TemplateInfo{Name: name, Source: "directory", Error: msg})
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// GetTemplate returns the details and parameter schema of a template
+func (tm *TemplateManager) GetTemplate(name string) (*TemplateDetail, error) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	entry, exists := tm.templates[name]
+	if !exists {
+		return nil, fmt.Errorf("template %s not found", name)
+	}
+
+	return &TemplateDetail{
+		TemplateInfo: tm.templateInfo(name, entry),
+		Schema:       entry.schema.Public(),
+	}, nil
+}
+
+// GetSchema returns the parameter schema of a template, or nil if it declares none
+func (tm *TemplateManager) GetSchema(name string) (*TemplateSchema, error) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	entry, exists := tm.templates[name]
+	if !exists {
+		return nil, fmt.Errorf("template %s not found", name)
+	}
+	return entry.schema, nil
+}
+
+// templateInfo builds the info of a loaded template; callers must hold tm.mu
+func (tm *TemplateManager) templateInfo(name string, entry *templateEntry) TemplateInfo {
+	info := TemplateInfo{
+		Name:     name,
+		Source:   entry.source,
+		Path:     entry.path,
+		LoadedAt: entry.loadedAt,
+		Error:    tm.errors[name],
+	}
+	if entry.schema != nil {
+		info.Description = entry.schema.Description
+	}
+	if entry.source == "directory" {
+		info.Revision = entry.revision
+	}
+	return info
+}
+
+// GetLoadErrors returns the current load errors keyed by template name
+func (tm *TemplateManager) GetLoadErrors() map[string]string {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	errs := make(map[string]string, len(tm.errors))
+	for name, msg := range tm.errors {
+		errs[name] = msg
+	}
+	return errs
+}
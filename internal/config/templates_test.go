@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/achyar10/go-zteolt/templates"
+)
+
+func TestResolve(t *testing.T) {
+	fsys := fstest.MapFS{
+		"add-onu.tmpl":               {Data: []byte("con t\n")},
+		"add-onu-ipoe.tmpl":          {Data: []byte("con t\n")},
+		"show-version.tmpl":          {Data: []byte("show version\n")},
+		"c600/add-onu.tmpl":          {Data: []byte("configure terminal\n")},
+		"c600/v1.2/add-onu.tmpl":     {Data: []byte("configure terminal\n")},
+		"c650/delete-onu.tmpl":       {Data: []byte("configure terminal\n")},
+		"huawei/add-onu.tmpl":        {Data: []byte("config\n")},
+		"huawei/ma5800/add-onu.tmpl": {Data: []byte("config\n")},
+	}
+	tm, err := NewTemplateManager("", fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, model, firmware string
+		want                  string
+		wantErr               bool
+	}{
+		{"add-onu", "", "", "add-onu", false},
+		{"add-onu", "C300", "V2.1", "add-onu", false},
+		{"add-onu", "ZXA10 C320", "", "add-onu", false},
+		{"add-onu", "C600", "", "c600/add-onu", false},
+		{"add-onu", "C600", "V1.2", "c600/v1.2/add-onu", false},
+		{"add-onu", "C620", "V1.2", "c600/add-onu", false},
+		{"add-onu", "C650", "V1.1", "c600/add-onu", false},
+		{"delete-onu", "C650", "", "c650/delete-onu", false},
+		{"add-onu-ipoe", "C600", "", "", true},
+		{"add-onu-ipoe", "C650", "V1.2", "", true},
+		{"show-version", "C620", "", "", true},
+		{"huawei/add-onu", "MA5800", "", "huawei/ma5800/add-onu", false},
+		{"huawei/add-onu", "MA5600", "", "huawei/add-onu", false},
+	}
+
+	for _, tt := range tests {
+		got, err := tm.Resolve(tt.name, tt.model, tt.firmware)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Resolve(%q, %q, %q) error = %v, wantErr %v", tt.name, tt.model, tt.firmware, err, tt.wantErr)
+			continue
+		}
+		if err != nil && !errors.Is(err, ErrTemplateNotFound) {
+			t.Errorf("Resolve(%q, %q, %q) error = %v, want ErrTemplateNotFound", tt.name, tt.model, tt.firmware, err)
+		}
+		if got != tt.want {
+			t.Errorf("Resolve(%q, %q, %q) = %q, want %q", tt.name, tt.model, tt.firmware, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTemplateValidates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"set-name.tmpl": {Data: []byte(`---
+{"params": [{"name": "Name", "type": "string", "required": true, "pattern": "[a-z]+"}]}
+---
+name {{.Name}}
+`)},
+	}
+	tm, err := NewTemplateManager("", fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, params := range []map[string]any{nil, {}, {"Name": "BAD NAME"}} {
+		_, _, err := tm.RenderTemplate("set-name", params)
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("RenderTemplate(%v) error = %v, want a ValidationError", params, err)
+		}
+	}
+
+	commands, _, err := tm.RenderTemplate("set-name", map[string]any{"Name": "olt"})
+	if err != nil || len(commands) != 1 || commands[0] != "name olt" {
+		t.Errorf("RenderTemplate = %q, %v; want [name olt]", commands, err)
+	}
+}
+
+func TestRenderAddONUName(t *testing.T) {
+	tm, err := NewTemplateManager("", templates.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := func(name string) map[string]any {
+		return map[string]any{
+			"Board": 2, "Pon": 1, "Onu": 5, "SerialNumber": "ZTEGC0000001",
+			"Name": name, "SecretPassword": "secret", "Description": "customer",
+			"VlanID": 100, "TcontProfile": "T-100M", "TrafficLimit": "DOWN-100M",
+		}
+	}
+
+	for _, name := range []string{"user+1@isp", "user 1", "user;1"} {
+		_, _, err := tm.RenderTemplate("add-onu", params(name))
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("RenderTemplate(Name=%q) error = %v, want a ValidationError", name, err)
+		}
+	}
+
+	commands, _, err := tm.RenderTemplate("add-onu", params("user.1@isp-a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(commands, "pppoe 1 nat enable user user.1@isp-a password secret") {
+		t.Errorf("RenderTemplate did not use the name unchanged as PPPoE username:\n%s", strings.Join(commands, "\n"))
+	}
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/achyar10/go-zteolt/internal/utils"
+)
+
+// DefaultThresholdProfile is the built-in optical threshold profile
+const DefaultThresholdProfile = "default"
+
+// OLTThresholds assigns threshold profiles to an OLT and its PON ports
+type OLTThresholds struct {
+	Profile string            `json:"profile,omitempty"` // profile for the whole OLT
+	PONs    map[string]string `json:"pons,omitempty"`    // "board/pon" -> profile
+}
+
+// thresholdFile is the layout of the optical thresholds file
+type thresholdFile struct {
+	Default  string                     `json:"default,omitempty"`
+	Profiles map[string]json.RawMessage `json:"profiles"`
+	OLTs     map[string]OLTThresholds   `json:"olts"`
+}
+
+// ThresholdRegistry holds optical threshold profiles and their assignment to
+// OLTs (by host) and PON ports
+type ThresholdRegistry struct {
+	path string
+
+	mu       sync.RWMutex
+	fallback string
+	profiles map[string]utils.OpticalThresholds
+	olts     map[string]OLTThresholds
+}
+
+// NewThresholdRegistry loads threshold profiles from a JSON file. An empty path
+// yields a registry with only the built-in default profile.
+func NewThresholdRegistry(path string) (*ThresholdRegistry, error) {
+	r := &ThresholdRegistry{path: path}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads the thresholds file
+func (r *ThresholdRegistry) Reload() error {
+	profiles := map[string]utils.OpticalThresholds{
+		DefaultThresholdProfile: utils.DefaultOpticalThresholds,
+	}
+	file := thresholdFile{Default: DefaultThresholdProfile}
+
+	if r.path != "" {
+		data, err := os.ReadFile(r.path)
+		if err != nil {
+			return fmt.Errorf("failed to read thresholds file %s: %w", r.path, err)
+		}
+		if err := json.Unmarshal(data, &file); err != nil {
+			return fmt.Errorf("invalid thresholds file %s: %w", r.path, err)
+		}
+		if file.Default == "" {
+			file.Default = DefaultThresholdProfile
+		}
+	}
+
+	for name, raw := range file.Profiles {
+		// Values missing from a profile keep the built-in defaults
+		t := utils.DefaultOpticalThresholds
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return fmt.Errorf("threshold profile %s: %w", name, err)
+		}
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("threshold profile %s: %w", name, err)
+		}
+		profiles[name] = t
+	}
+
+	if _, ok := profiles[file.Default]; !ok {
+		return fmt.Errorf("default threshold profile %s is not defined", file.Default)
+	}
+	for host, o := range file.OLTs {
+		if _, ok := profiles[o.Profile]; o.Profile != "" && !ok {
+			return fmt.Errorf("olt %s: unknown threshold profile %s", host, o.Profile)
+		}
+		for pon, name := range o.PONs {
+			if err := checkPONKey(pon); err != nil {
+				return fmt.Errorf("olt %s: %w", host, err)
+			}
+			if _, ok := profiles[name]; !ok {
+				return fmt.Errorf("olt %s pon %s: unknown threshold profile %s", host, pon, name)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.fallback = file.Default
+	r.profiles = profiles
+	r.olts = file.OLTs
+	r.mu.Unlock()
+	return nil
+}
+
+// For returns the threshold profile applying to a PON port of an OLT: the PON
+// assignment, then the OLT assignment, then the default profile. A board or
+// pon of 0 skips the PON lookup.
+func (r *ThresholdRegistry) For(host string, board, pon int) (string, utils.OpticalThresholds) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name := r.fallback
+	if o, ok := r.olts[host]; ok {
+		if o.Profile != "" {
+			name = o.Profile
+		}
+		if p, ok := o.PONs[fmt.Sprintf("%d/%d", board, pon)]; ok && board > 0 && pon > 0 {
+			name = p
+		}
+	}
+	return name, r.profiles[name]
+}
+
+// Profiles returns all threshold profiles by name
+func (r *ThresholdRegistry) Profiles() map[string]utils.OpticalThresholds {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]utils.OpticalThresholds, len(r.profiles))
+	for name, t := range r.profiles {
+		out[name] = t
+	}
+	return out
+}
+
+// Assignments returns the default profile name and the per-OLT assignments
+func (r *ThresholdRegistry) Assignments() (string, map[string]OLTThresholds) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]OLTThresholds, len(r.olts))
+	for host, o := range r.olts {
+		out[host] = o
+	}
+	return r.fallback, out
+}
+
+// checkPONKey validates a "board/pon" key
+func checkPONKey(key string) error {
+	b, p, ok := strings.Cut(key, "/")
+	board, errB := strconv.Atoi(b)
+	pon, errP := strconv.Atoi(p)
+	if !ok || errB != nil || errP != nil || board < 1 || pon < 1 {
+		return fmt.Errorf("invalid pon key %q, expected board/pon", key)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// DefaultTrapListen is the address the SNMP trap receiver listens on by default
+const DefaultTrapListen = "0.0.0.0:162"
+
+// TrapConfig configures the SNMP trap receiver
+type TrapConfig struct {
+	Listen      string      `json:"listen,omitempty"`      // default: 0.0.0.0:162
+	Communities []string    `json:"communities,omitempty"` // v1/v2c communities accepted (default: any)
+	V3          *TrapV3User `json:"v3,omitempty"`          // USM user of v3 traps and informs
+	BufferSize  int         `json:"buffer_size,omitempty"` // recent events kept (default: 1000)
+	Sinks       []TrapSink  `json:"sinks,omitempty"`
+}
+
+// TrapV3User holds the USM user SNMPv3 traps and informs are authenticated with
+type TrapV3User struct {
+	Username      string `json:"username"`
+	SecurityLevel string `json:"security_level,omitempty"` // noAuthNoPriv, authNoPriv or authPriv
+	AuthProtocol  string `json:"auth_protocol,omitempty"`  // MD5, SHA, SHA224, SHA256, SHA384, SHA512
+	AuthPassword  string `json:"auth_password,omitempty"`
+	PrivProtocol  string `json:"priv_protocol,omitempty"` // DES, AES, AES192, AES256, AES192C, AES256C
+	PrivPassword  string `json:"priv_password,omitempty"`
+	EngineID      string `json:"engine_id,omitempty"` // hex engine ID of the OLT sending the traps
+}
+
+// TrapSink describes where decoded trap events are forwarded
+type TrapSink struct {
+	Type    string            `json:"type"`              // webhook, log or file
+	URL     string            `json:"url,omitempty"`     // webhook: endpoint the events are posted to
+	Headers map[string]string `json:"headers,omitempty"` // webhook: e.g. Authorization
+	Timeout int               `json:"timeout,omitempty"` // webhook: seconds (default: 5)
+	Path    string            `json:"path,omitempty"`    // file: JSON lines are appended to it
+	Events  []string          `json:"events,omitempty"`  // event types forwarded (default: all)
+}
+
+// LoadTrapConfig reads the trap receiver configuration from a JSON file
+func LoadTrapConfig(path string) (*TrapConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read traps file %s: %w", path, err)
+	}
+
+	var cfg TrapConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid traps file %s: %w", path, err)
+	}
+	if cfg.Listen == "" {
+		cfg.Listen = DefaultTrapListen
+	}
+	if cfg.BufferSize < 0 {
+		return nil, fmt.Errorf("traps file %s: buffer_size must not be negative", path)
+	}
+	if cfg.V3 != nil && cfg.V3.Username == "" {
+		return nil, fmt.Errorf("traps file %s: v3 requires a username", path)
+	}
+
+	for i, sink := range cfg.Sinks {
+		switch sink.Type {
+		case "webhook":
+			u, err := url.Parse(sink.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("traps file %s: sink %d: webhook requires an http(s) url", path, i+1)
+			}
+		case "file":
+			if sink.Path == "" {
+				return nil, fmt.Errorf("traps file %s: sink %d: file requires a path", path, i+1)
+			}
+		case "log":
+		default:
+			return nil, fmt.Errorf("traps file %s: sink %d: unknown type %q (supported: webhook, log, file)", path, i+1, sink.Type)
+		}
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,118 @@
+package olt
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/achyar10/go-zteolt/internal/utils"
+)
+
+// DefaultVendor is used when a request or device does not name a vendor
+const DefaultVendor = "zte"
+
+// ErrUnsupportedVendor is returned when no driver is registered for a vendor
+var ErrUnsupportedVendor = errors.New("unsupported OLT vendor")
+
+// CLIProfile describes the login and paging behaviour of a vendor CLI
+type CLIProfile struct {
+	Prompt         string   // regex matching the command prompt
+	UsernamePrompt string   // regex matching the username prompt
+	PasswordPrompt string   // regex matching the password prompt
+	Setup          []string // commands run after login, e.g. to disable paging
+}
+
+// Driver encapsulates the vendor specific behaviour of an OLT
+type Driver interface {
+	// Vendor returns the key the driver is registered under
+	Vendor() string
+
+	// CLI returns the prompts and session setup of the vendor CLI
+	CLI() CLIProfile
+
+	// Template returns the command template implementing an operation such as
+	// add-onu, delete-onu, reboot-onu, save-config, check-unconfigured,
+	// check-attenuation, check-onu-state, check-onu-detail or check-pon-power
+	Template(op string) string
+
+	// CommandError returns the first CLI error line found in command output
+	CommandError(output string) string
+
+	// ParseUnconfigured parses the output of the check-unconfigured template
+	ParseUnconfigured(host, output string) *utils.UnconfiguredONUList
+
+	// ParseOptical parses the output of the check-attenuation template
+	ParseOptical(host string, board, pon, onu int, output string) *utils.AttenuationData
+
+	// ParseONUState parses the output of the check-onu-state template
+	ParseONUState(host, output string) *utils.ONUStateList
+
+	// ParseONUDetail parses the output of the check-onu-detail template
+	ParseONUDetail(host, output string) *utils.ONUDetail
+
+	// ParsePONPower parses the output of the check-pon-power template,
+	// estimating attenuation from the given OLT transmit power
+	ParsePONPower(host string, board, pon int, oltTxPower float64, output string) *utils.PONPowerSweep
+
+	// OIDIndexes returns the values of the OID profile placeholders for a
+	// PON port, e.g. {"if_index": 285278465}; the port ifIndex is set when it
+	// was discovered from ifDescr
+	OIDIndexes(port PONPort) (map[string]int, error)
+
+	// PONPortFromIndexes returns the PON port that OID profile placeholder
+	// values, as found in the varbinds of a trap, point to
+	PONPortFromIndexes(indexes map[string]int) (PONPort, bool)
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]Driver)
+)
+
+// RegisterDriver makes a driver available under its vendor key
+func RegisterDriver(d Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	vendor := strings.ToLower(d.Vendor())
+	if _, dup := drivers[vendor]; dup {
+		panic("olt: driver registered twice for vendor " + vendor)
+	}
+	drivers[vendor] = d
+}
+
+// GetDriver returns the driver for a vendor, using DefaultVendor when empty
+func GetDriver(vendor string) (Driver, error) {
+	vendor = strings.ToLower(strings.TrimSpace(vendor))
+	if vendor == "" {
+		vendor = DefaultVendor
+	}
+
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	d, ok := drivers[vendor]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (supported: %s)", ErrUnsupportedVendor, vendor, strings.Join(vendorsLocked(), ", "))
+	}
+	return d, nil
+}
+
+// Vendors returns the registered vendor keys
+func Vendors() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	return vendorsLocked()
+}
+
+// vendorsLocked returns the sorted vendor keys; callers must hold driversMu
+func vendorsLocked() []string {
+	vendors := make([]string, 0, len(drivers))
+	for v := range drivers {
+		vendors = append(vendors, v)
+	}
+	sort.Strings(vendors)
+	return vendors
+}
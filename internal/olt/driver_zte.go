@@ -0,0 +1,196 @@
+package olt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/achyar10/go-zteolt/internal/utils"
+)
+
+// zteCommandErrorRE matches error lines printed by the ZTE CLI
+var zteCommandErrorRE = regexp.MustCompile(`(?m)^\s*(%Error.*|%Code \d+.*|% Invalid input.*)$`)
+
+// zteInterfaceRE matches lines starting with a C300 or C600 style PON/ONU interface
+var zteInterfaceRE = regexp.MustCompile(`^[a-z0-9-]+[-_](olt|onu)[-_]\d`)
+
+// ZTEDriver implements Driver for ZTE C300/C320/C600 OLTs
+type ZTEDriver struct{}
+
+func init() {
+	RegisterDriver(ZTEDriver{})
+}
+
+// Vendor returns the vendor key of the driver
+func (ZTEDriver) Vendor() string {
+	return "zte"
+}
+
+// CLI returns the ZTE CLI prompts and paging setup
+func (ZTEDriver) CLI() CLIProfile {
+	return CLIProfile{
+		Prompt:         `(?m)[>#]\s?$`,
+		UsernamePrompt: `(?i)(username|login)\s*:\s*$`,
+		PasswordPrompt: `(?i)password\s*:\s*$`,
+		Setup:          []string{"terminal length 0"},
+	}
+}
+
+// Template returns the command template of an operation; ZTE templates live at
+// the top level of the template directory
+func (ZTEDriver) Template(op string) string {
+	return op
+}
+
+// CommandError returns the first ZTE CLI error line found in the output
+func (ZTEDriver) CommandError(output string) string {
+	if m := zteCommandErrorRE.FindStringSubmatch(output); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
+
+// ParseUnconfigured parses the output of "show pon onu uncfg"
+func (ZTEDriver) ParseUnconfigured(host, output string) *utils.UnconfiguredONUList {
+	return utils.ParseUnconfiguredONUOutput(host, extractUnconfiguredOutput(output))
+}
+
+// ParseOptical parses the output of "show pon power attenuation"
+func (ZTEDriver) ParseOptical(host string, board, pon, onu int, output string) *utils.AttenuationData {
+	return utils.ParseAttenuationOutput(host, board, pon, onu, extractAttenuationOutput(output))
+}
+
+// ParseONUState parses the output of "show gpon onu state"
+func (ZTEDriver) ParseONUState(host, output string) *utils.ONUStateList {
+	return utils.ParseONUStateOutput(host, output)
+}
+
+// ParseONUDetail parses the output of "show gpon onu detail-info"
+func (ZTEDriver) ParseONUDetail(host, output string) *utils.ONUDetail {
+	return utils.ParseONUDetailOutput(host, output)
+}
+
+// ParsePONPower parses the output of "show pon power onu-rx" and "show pon power olt-rx"
+func (ZTEDriver) ParsePONPower(host string, board, pon int, oltTxPower float64, output string) *utils.PONPowerSweep {
+	return utils.ParsePONPowerOutput(host, board, pon, oltTxPower, output)
+}
+
+// OIDIndexes returns the PON port indexes of the 1082 ({if_index}) and 1012
+// ({ext_if_index}) MIB trees used by the ZTE OID profiles
+func (ZTEDriver) OIDIndexes(port PONPort) (map[string]int, error) {
+	if port.Slot < 1 || port.Slot > 255 || port.Port < 1 || port.Port > 255 {
+		return nil, fmt.Errorf("%w: %s", ErrPONNotFound, port)
+	}
+
+	// Prefer the ifIndex discovered from ifDescr over the computed one. It is
+	// in the 1082 form (port in the low byte) or, on firmware numbering
+	// IF-MIB like the 1012 tree, in the 1012 form (low byte 0).
+	interfaceIndex := zteInterfaceIndex(port)
+	onuTypeIndex := zteONUTypeIndex(port)
+	switch {
+	case port.IfIndex == 0:
+	case port.IfIndex&0xff != 0:
+		interfaceIndex = port.IfIndex
+	default:
+		onuTypeIndex = port.IfIndex
+	}
+
+	return map[string]int{
+		"if_index":     interfaceIndex,
+		"ext_if_index": onuTypeIndex,
+	}, nil
+}
+
+// PONPortFromIndexes decodes the PON port of an if_index or ext_if_index,
+// in either the 1082 or the 1012 form
+func (ZTEDriver) PONPortFromIndexes(indexes map[string]int) (PONPort, bool) {
+	for _, name := range []string{"if_index", "ext_if_index"} {
+		index, ok := indexes[name]
+		if !ok || index>>28 != 1 {
+			continue
+		}
+		if index&0xff != 0 {
+			return PONPort{Rack: index >> 24 & 0xf, Shelf: index >> 16 & 0xff, Slot: index >> 8 & 0xff, Port: index & 0xff, IfIndex: index}, true
+		}
+		return PONPort{Rack: 1, Shelf: 1, Slot: index >> 16 & 0xff, Port: index >> 8 & 0xff}, true
+	}
+	return PONPort{}, false
+}
+
+// zteInterfaceIndex computes the PON port index of the 1082 MIB tree, which
+// packs type 1 (PON), rack, shelf, slot and port into one integer:
+//
+//	1/1/1/1 (rack/shelf/slot/port): 285278465 (0x11010101)
+//	1/1/2/4:                        285278724 (0x11010204)
+func zteInterfaceIndex(p PONPort) int {
+	return 1<<28 | (orOne(p.Rack)&0xf)<<24 | (orOne(p.Shelf)&0xff)<<16 | (p.Slot&0xff)<<8 | p.Port&0xff
+}
+
+// zteONUTypeIndex computes the PON port index of the 1012 MIB tree, which
+// holds only slot and port:
+//
+//	slot 1 port 1: 268501248 (0x10010100)
+//	slot 2 port 4: 268567552 (0x10020400)
+func zteONUTypeIndex(p PONPort) int {
+	return 1<<28 | (p.Slot&0xff)<<16 | (p.Port&0xff)<<8
+}
+
+// extractAttenuationOutput extracts the actual attenuation data from the full command output
+func extractAttenuationOutput(fullOutput string) string {
+	lines := strings.Split(fullOutput, "\n")
+	var outputLines []string
+	inData := false
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		// Skip headers and prompts
+		if strings.Contains(line, "===") || strings.HasPrefix(line, ">>>") ||
+			strings.HasPrefix(line, "ZXAN") || line == "" {
+			continue
+		}
+
+		// Start collecting data when we see relevant content
+		if strings.Contains(line, "OLT") || strings.Contains(line, "ONU") ||
+			strings.Contains(line, "Attenuation") || strings.Contains(line, "up") ||
+			strings.Contains(line, "down") {
+			inData = true
+		}
+
+		if inData {
+			outputLines = append(outputLines, line)
+		}
+	}
+
+	return strings.Join(outputLines, "\n")
+}
+
+// extractUnconfiguredOutput extracts the actual unconfigured ONU data from the full command output
+func extractUnconfiguredOutput(fullOutput string) string {
+	lines := strings.Split(fullOutput, "\n")
+	var outputLines []string
+	inData := false
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		// Skip headers and prompts
+		if strings.Contains(line, "===") || strings.HasPrefix(line, ">>>") ||
+			strings.HasPrefix(line, "ZXAN") || line == "" {
+			continue
+		}
+
+		// Start collecting data when we see relevant content
+		if strings.Contains(line, "OltIndex") || strings.Contains(line, "OnuIndex") ||
+			strings.Contains(line, "Model") || strings.Contains(line, "SN") || strings.Contains(line, "Interface") ||
+			zteInterfaceRE.MatchString(line) {
+			inData = true
+		}
+
+		if inData {
+			outputLines = append(outputLines, line)
+		}
+	}
+
+	return strings.Join(outputLines, "\n")
+}
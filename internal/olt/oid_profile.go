@@ -0,0 +1,421 @@
+package olt
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// oidProfilesJSON holds the built-in OID profiles
+//
+//go:embed oid_profiles.json
+var oidProfilesJSON []byte
+
+// OIDProfile maps the ONU table columns of an OLT family to OIDs. Column OIDs
+// may contain placeholders such as {if_index} that the vendor driver fills
+// in for each PON port (see Driver.OIDIndexes). The pon_* columns are the
+// OIDs of a PON port reading itself rather than table columns; the card_*,
+// fan_* and power_* columns are OLT-wide tables without placeholders.
+type OIDProfile struct {
+	Name        string            `json:"name"`
+	Vendor      string            `json:"vendor"`
+	Description string            `json:"description,omitempty"`
+	Extends     string            `json:"extends,omitempty"`   // profile whose columns are inherited
+	Models      []string          `json:"models,omitempty"`    // e.g. C320, C600; empty matches any model
+	Firmwares   []string          `json:"firmwares,omitempty"` // e.g. V2.1.0; empty matches any firmware
+	Columns     map[string]string `json:"columns"`
+
+	// Scales multiply the raw values of numeric columns into their unit,
+	// e.g. 0.001 for a temperature in thousandths of °C (default: 1)
+	Scales map[string]float64 `json:"scales,omitempty"`
+
+	// Enums name the raw values of status columns, e.g. {"1": "inService"}
+	Enums map[string]map[string]string `json:"enums,omitempty"`
+
+	// Traps map the OIDs of the traps the OLT sends to event types, e.g.
+	// {".1.3.6.1.4.1.3902.1082.500.10.2.3.0.4": "dying_gasp"}
+	Traps map[string]string `json:"traps,omitempty"`
+}
+
+// oidProfileFile is the layout of an OID profile file
+type oidProfileFile struct {
+	Profiles []OIDProfile `json:"profiles"`
+}
+
+// oidColumns lists the known columns; name is required
+var oidColumns = []string{
+	"name", "description", "serial_number", "type", "rx_power", "tx_power", "status",
+	"ip_address", "last_online", "last_offline", "last_offline_reason", "optical_distance",
+	"upstream_octets", "downstream_octets",
+	"pon_tx_power", "pon_temperature", "pon_bias_current", "pon_voltage",
+	"card_type", "card_status", "card_software_version", "card_cpu_usage", "card_memory_usage",
+	"card_temperature", "fan_status", "power_status",
+}
+
+// systemColumnPrefixes prefix the OLT-wide columns
+var systemColumnPrefixes = []string{"card_", "fan_", "power_"}
+
+// columnOIDRE matches a numeric OID with optional {placeholder} arcs
+var columnOIDRE = regexp.MustCompile(`^(\.(\d+|\{[a-z_]+\}))+$`)
+
+// placeholderRE matches the placeholders of a column OID
+var placeholderRE = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+var (
+	oidProfilesOnce sync.Once
+	oidProfilesMu   sync.RWMutex
+	oidProfiles     map[string]OIDProfile
+)
+
+// ensureOIDProfiles resolves the built-in profiles on first use, once the
+// drivers validating them have registered
+func ensureOIDProfiles() {
+	oidProfilesOnce.Do(func() {
+		profiles, err := resolveOIDProfiles(builtinOIDProfiles())
+		if err != nil {
+			panic("olt: invalid embedded OID profiles: " + err.Error())
+		}
+		oidProfilesMu.Lock()
+		oidProfiles = profiles
+		oidProfilesMu.Unlock()
+	})
+}
+
+// OIDProfiles returns the OID profiles, with inherited columns resolved, sorted by name
+func OIDProfiles() []OIDProfile {
+	ensureOIDProfiles()
+	oidProfilesMu.RLock()
+	defer oidProfilesMu.RUnlock()
+
+	list := make([]OIDProfile, 0, len(oidProfiles))
+	for _, p := range oidProfiles {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// GetOIDProfile returns an OID profile by name
+func GetOIDProfile(name string) (OIDProfile, bool) {
+	ensureOIDProfiles()
+	oidProfilesMu.RLock()
+	defer oidProfilesMu.RUnlock()
+
+	p, ok := oidProfiles[name]
+	return p, ok
+}
+
+// SelectOIDProfile returns the named OID profile or, when name is empty, the
+// profile of the vendor matching the OLT model and firmware most closely
+func SelectOIDProfile(vendor, model, firmware, name string) (OIDProfile, error) {
+	if vendor == "" {
+		vendor = DefaultVendor
+	}
+	vendor = strings.ToLower(vendor)
+
+	if name != "" {
+		p, ok := GetOIDProfile(name)
+		if !ok {
+			return OIDProfile{}, fmt.Errorf("%w: unknown OID profile %q", ErrSNMPConfig, name)
+		}
+		if p.Vendor != vendor {
+			return OIDProfile{}, fmt.Errorf("%w: OID profile %s is for vendor %s, not %s", ErrSNMPConfig, name, p.Vendor, vendor)
+		}
+		return p, nil
+	}
+	model, firmware = normalizeOIDKey(model), normalizeOIDKey(firmware)
+
+	best, bestScore := OIDProfile{}, -1
+	for _, p := range OIDProfiles() {
+		if p.Vendor != vendor {
+			continue
+		}
+		score := 0
+		if len(p.Models) > 0 {
+			if !slices.Contains(p.Models, model) {
+				continue
+			}
+			score += 2
+		}
+		if len(p.Firmwares) > 0 {
+			if !slices.Contains(p.Firmwares, firmware) {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = p, score
+		}
+	}
+	if bestScore < 0 {
+		return OIDProfile{}, fmt.Errorf("%w: no OID profile for vendor %s", ErrSNMPConfig, vendor)
+	}
+	return best, nil
+}
+
+// LoadOIDProfiles merges the profiles of a JSON file into the built-in
+// profiles; a profile with an existing name replaces it. The merged set is
+// validated before it is used.
+func LoadOIDProfiles(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read OID profiles %s: %w", path, err)
+	}
+
+	ensureOIDProfiles()
+	oidProfilesMu.Lock()
+	defer oidProfilesMu.Unlock()
+
+	raw := builtinOIDProfiles()
+	if err := decodeOIDProfiles(data, raw); err != nil {
+		return fmt.Errorf("invalid OID profiles %s: %w", path, err)
+	}
+	profiles, err := resolveOIDProfiles(raw)
+	if err != nil {
+		return fmt.Errorf("invalid OID profiles %s: %w", path, err)
+	}
+	oidProfiles = profiles
+	return nil
+}
+
+// Build returns the column OIDs of a PON port, filling the placeholders with
+// the indexes computed by the vendor driver
+func (p OIDProfile) Build(indexes map[string]int) (*OltConfig, error) {
+	oids := make(map[string]string, len(p.Columns))
+	for column, oid := range p.Columns {
+		var missing string
+		oids[column] = placeholderRE.ReplaceAllStringFunc(oid, func(m string) string {
+			index, ok := indexes[m[1:len(m)-1]]
+			if !ok {
+				missing = m
+			}
+			return strconv.Itoa(index)
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("%w: OID profile %s column %s: no value for %s", ErrSNMPConfig, p.Name, column, missing)
+		}
+	}
+
+	return &OltConfig{
+		Profile:                   p.Name,
+		OnuIDNameOID:              oids["name"],
+		OnuTypeOID:                oids["type"],
+		OnuSerialNumberOID:        oids["serial_number"],
+		OnuRxPowerOID:             oids["rx_power"],
+		OnuTxPowerOID:             oids["tx_power"],
+		OnuStatusOID:              oids["status"],
+		OnuIPAddressOID:           oids["ip_address"],
+		OnuDescriptionOID:         oids["description"],
+		OnuLastOnlineOID:          oids["last_online"],
+		OnuLastOfflineOID:         oids["last_offline"],
+		OnuLastOfflineReasonOID:   oids["last_offline_reason"],
+		OnuGponOpticalDistanceOID: oids["optical_distance"],
+		OnuUpstreamOctetsOID:      oids["upstream_octets"],
+		OnuDownstreamOctetsOID:    oids["downstream_octets"],
+		PonTxPowerOID:             oids["pon_tx_power"],
+		PonTemperatureOID:         oids["pon_temperature"],
+		PonBiasCurrentOID:         oids["pon_bias_current"],
+		PonVoltageOID:             oids["pon_voltage"],
+		Scales:                    p.Scales,
+	}, nil
+}
+
+// builtinOIDProfiles decodes the embedded profiles without resolving them
+func builtinOIDProfiles() map[string]OIDProfile {
+	raw := make(map[string]OIDProfile)
+	if err := decodeOIDProfiles(oidProfilesJSON, raw); err != nil {
+		panic("olt: invalid embedded OID profiles: " + err.Error())
+	}
+	return raw
+}
+
+// decodeOIDProfiles decodes a profile file into profiles by name
+func decodeOIDProfiles(data []byte, profiles map[string]OIDProfile) error {
+	var file oidProfileFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(file.Profiles))
+	for i, p := range file.Profiles {
+		if p.Name == "" {
+			return fmt.Errorf("profile %d has no name", i+1)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("duplicate profile %s", p.Name)
+		}
+		seen[p.Name] = true
+		profiles[p.Name] = p
+	}
+	return nil
+}
+
+// resolveOIDProfiles applies inheritance and validates every profile
+func resolveOIDProfiles(raw map[string]OIDProfile) (map[string]OIDProfile, error) {
+	resolved := make(map[string]OIDProfile, len(raw))
+
+	var resolve func(name string, chain []string) (OIDProfile, error)
+	resolve = func(name string, chain []string) (OIDProfile, error) {
+		if p, ok := resolved[name]; ok {
+			return p, nil
+		}
+		for _, c := range chain {
+			if c == name {
+				return OIDProfile{}, fmt.Errorf("profile %s: inheritance cycle %s", chain[0], strings.Join(append(chain, name), " -> "))
+			}
+		}
+		p, ok := raw[name]
+		if !ok {
+			return OIDProfile{}, fmt.Errorf("profile %s extends unknown profile %s", chain[len(chain)-1], name)
+		}
+
+		columns := make(map[string]string)
+		scales := make(map[string]float64)
+		enums := make(map[string]map[string]string)
+		traps := make(map[string]string)
+		if p.Extends != "" {
+			parent, err := resolve(p.Extends, append(chain, name))
+			if err != nil {
+				return OIDProfile{}, err
+			}
+			if p.Vendor == "" {
+				p.Vendor = parent.Vendor
+			}
+			for column, oid := range parent.Columns {
+				columns[column] = oid
+			}
+			for column, scale := range parent.Scales {
+				scales[column] = scale
+			}
+			for column, names := range parent.Enums {
+				enums[column] = names
+			}
+			for oid, event := range parent.Traps {
+				traps[oid] = event
+			}
+		}
+		for column, oid := range p.Columns {
+			columns[column] = oid
+		}
+		for column, scale := range p.Scales {
+			scales[column] = scale
+		}
+		for column, names := range p.Enums {
+			enums[column] = names
+		}
+		for oid, event := range p.Traps {
+			traps[oid] = event
+		}
+		p.Columns = columns
+		p.Scales = scales
+		p.Enums = enums
+		p.Traps = traps
+		p.Vendor = strings.ToLower(p.Vendor)
+		for i := range p.Models {
+			p.Models[i] = normalizeOIDKey(p.Models[i])
+		}
+		for i := range p.Firmwares {
+			p.Firmwares[i] = normalizeOIDKey(p.Firmwares[i])
+		}
+
+		if err := validateOIDProfile(p); err != nil {
+			return OIDProfile{}, fmt.Errorf("profile %s: %w", name, err)
+		}
+		resolved[name] = p
+		return p, nil
+	}
+
+	for name := range raw {
+		if _, err := resolve(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// validateOIDProfile checks the vendor, the column names and OIDs, and that
+// the vendor driver provides every placeholder used
+func validateOIDProfile(p OIDProfile) error {
+	driver, err := GetDriver(p.Vendor)
+	if err != nil {
+		return err
+	}
+	indexes, err := driver.OIDIndexes(PONPort{Rack: 1, Shelf: 1, Slot: 1, Port: 1})
+	if err != nil {
+		return err
+	}
+
+	if p.Columns["name"] == "" {
+		return fmt.Errorf("column name is required")
+	}
+	for column, oid := range p.Columns {
+		if !slices.Contains(oidColumns, column) {
+			return fmt.Errorf("unknown column %s (supported: %s)", column, strings.Join(oidColumns, ", "))
+		}
+		if !columnOIDRE.MatchString(oid) {
+			return fmt.Errorf("column %s: invalid OID %q", column, oid)
+		}
+		if isSystemColumn(column) && placeholderRE.MatchString(oid) {
+			return fmt.Errorf("column %s: OLT-wide columns take no placeholders", column)
+		}
+		for _, m := range placeholderRE.FindAllStringSubmatch(oid, -1) {
+			if _, ok := indexes[m[1]]; !ok {
+				return fmt.Errorf("column %s: unknown placeholder {%s} for vendor %s", column, m[1], p.Vendor)
+			}
+		}
+	}
+	for column, scale := range p.Scales {
+		if !slices.Contains(oidColumns, column) {
+			return fmt.Errorf("scale of unknown column %s", column)
+		}
+		if scale <= 0 {
+			return fmt.Errorf("column %s: scale must be positive", column)
+		}
+	}
+	for column := range p.Enums {
+		if !slices.Contains(oidColumns, column) {
+			return fmt.Errorf("enums of unknown column %s", column)
+		}
+	}
+	for oid, event := range p.Traps {
+		if !columnOIDRE.MatchString(oid) || placeholderRE.MatchString(oid) {
+			return fmt.Errorf("trap %q: invalid OID", oid)
+		}
+		if !slices.Contains(TrapEventTypes, TrapEventType(event)) {
+			return fmt.Errorf("trap %s: unknown event type %q", oid, event)
+		}
+	}
+	return nil
+}
+
+// isSystemColumn reports whether a column is an OLT-wide table column
+func isSystemColumn(column string) bool {
+	for _, prefix := range systemColumnPrefixes {
+		if strings.HasPrefix(column, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// enumName returns the name of a raw status value, or the value itself
+func (p OIDProfile) enumName(column, value string) string {
+	if name, ok := p.Enums[column][value]; ok {
+		return name
+	}
+	return value
+}
+
+// normalizeOIDKey turns a model or firmware name such as "ZXA10 C600" into
+// its lower case form without spaces and ZXA10 prefix ("c600")
+func normalizeOIDKey(s string) string {
+	s = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "zxa10")
+	return strings.Join(strings.Fields(s), "")
+}
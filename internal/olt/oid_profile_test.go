@@ -0,0 +1,124 @@
+package olt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadTestOIDProfiles loads a profile file over the built-in profiles and
+// restores the previous profiles when the test ends
+func loadTestOIDProfiles(t *testing.T, data string) error {
+	t.Helper()
+	ensureOIDProfiles()
+	oidProfilesMu.RLock()
+	saved := oidProfiles
+	oidProfilesMu.RUnlock()
+	t.Cleanup(func() {
+		oidProfilesMu.Lock()
+		oidProfiles = saved
+		oidProfilesMu.Unlock()
+	})
+
+	path := filepath.Join(t.TempDir(), "oid_profiles.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadOIDProfiles(path)
+}
+
+func TestSelectOIDProfile(t *testing.T) {
+	err := loadTestOIDProfiles(t, `{"profiles": [
+		{"name": "zte-c600", "extends": "zte", "models": ["C600", "C650"],
+		 "columns": {"rx_power": ".1.3.6.1.4.1.3902.1082.500.20.2.2.2.1.20.{if_index}"}},
+		{"name": "zte-c600-v1.2", "extends": "zte-c600", "models": ["C600"], "firmwares": ["V1.2"],
+		 "columns": {"status": ".1.3.6.1.4.1.3902.1082.500.10.2.3.8.1.14.{if_index}"}}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		model, firmware, name string
+		want                  string
+	}{
+		{"", "", "", "zte"},
+		{"C320", "V2.1", "", "zte"},
+		{"C300", "V1.2", "", "zte"},
+		{"ZXA10 C650", "V1.2", "", "zte-c600"},
+		{"C600", "V1.1", "", "zte-c600"},
+		{"zxa10 c600", "v1.2", "", "zte-c600-v1.2"},
+		{"C600", "V1.2", "zte", "zte"},
+	}
+	for _, tt := range tests {
+		p, err := SelectOIDProfile("zte", tt.model, tt.firmware, tt.name)
+		if err != nil || p.Name != tt.want {
+			t.Errorf("SelectOIDProfile(%q, %q, %q) = %s, %v; want %s", tt.model, tt.firmware, tt.name, p.Name, err, tt.want)
+		}
+	}
+
+	base, _ := GetOIDProfile("zte")
+	p, _ := GetOIDProfile("zte-c600-v1.2")
+	if p.Vendor != "zte" {
+		t.Errorf("vendor = %q, want zte inherited", p.Vendor)
+	}
+	columns := map[string]string{
+		"name":     base.Columns["name"],
+		"rx_power": ".1.3.6.1.4.1.3902.1082.500.20.2.2.2.1.20.{if_index}",
+		"status":   ".1.3.6.1.4.1.3902.1082.500.10.2.3.8.1.14.{if_index}",
+	}
+	for column, want := range columns {
+		if p.Columns[column] != want {
+			t.Errorf("column %s = %q, want %q", column, p.Columns[column], want)
+		}
+	}
+	if len(p.Columns) != len(base.Columns) || len(p.Traps) != len(base.Traps) || p.Scales["pon_voltage"] != 0.001 {
+		t.Errorf("profile did not inherit the columns, traps and scales of zte: %+v", p)
+	}
+	if base.Columns["status"] == columns["status"] {
+		t.Error("loading an extending profile changed the base profile")
+	}
+
+	if _, err := SelectOIDProfile("zte", "", "", "missing"); err == nil {
+		t.Error("SelectOIDProfile accepted an unknown profile name")
+	}
+	if _, err := SelectOIDProfile("huawei", "", "", ""); err == nil {
+		t.Error("SelectOIDProfile returned a profile for a vendor without profiles")
+	}
+}
+
+func TestLoadOIDProfilesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		wantErr string
+	}{
+		{"unknown placeholder", `{"name": "x", "extends": "zte", "columns": {"status": ".1.3.6.{onu_index}"}}`, "unknown placeholder {onu_index}"},
+		{"malformed placeholder", `{"name": "x", "extends": "zte", "columns": {"status": ".1.3.6.{IfIndex}"}}`, "invalid OID"},
+		{"placeholder in OLT-wide column", `{"name": "x", "extends": "zte", "columns": {"fan_status": ".1.3.6.{if_index}"}}`, "take no placeholders"},
+		{"unknown column", `{"name": "x", "extends": "zte", "columns": {"uptime": ".1.3.6.1"}}`, "unknown column uptime"},
+		{"no name column", `{"name": "x", "vendor": "zte", "columns": {"status": ".1.3.6.1"}}`, "column name is required"},
+		{"unknown parent", `{"name": "x", "extends": "zte-c900", "columns": {}}`, "extends unknown profile zte-c900"},
+		{"unknown vendor", `{"name": "x", "vendor": "acme", "columns": {"name": ".1.3.6.1"}}`, "acme"},
+		{"unknown trap event", `{"name": "x", "extends": "zte", "columns": {}, "traps": {".1.3.6.1.9": "reboot"}}`, `unknown event type "reboot"`},
+	}
+
+	for _, tt := range tests {
+		err := loadTestOIDProfiles(t, `{"profiles": [`+tt.profile+`]}`)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want it to contain %q", tt.name, err, tt.wantErr)
+		}
+		if _, ok := GetOIDProfile("x"); ok {
+			t.Errorf("%s: rejected profile was loaded", tt.name)
+		}
+	}
+
+	err := loadTestOIDProfiles(t, `{"profiles": [
+		{"name": "a", "extends": "b", "columns": {}},
+		{"name": "b", "extends": "a", "columns": {}}
+	]}`)
+	if err == nil || !strings.Contains(err.Error(), "inheritance cycle") {
+		t.Errorf("cycle: error = %v, want an inheritance cycle", err)
+	}
+}
@@ -20,11 +20,12 @@ func NewService(timeout time.Duration) *Service {
 
 // OLTRequest represents a request to OLT device
 type OLTRequest struct {
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	User     string `json:"user"`
-	Password string `json:"password"`
-	Prompt   string `json:"prompt"`
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	User     string   `json:"user"`
+	Password string   `json:"password"`
+	Prompt   string   `json:"prompt"`
+	Vendor   string   `json:"vendor,omitempty"` // selects the driver (default: zte)
 	Commands []string `json:"commands"`
 }
 
@@ -46,7 +47,7 @@ func (s *Service) ExecuteCommands(ctx context.Context, req OLTRequest) (*OLTResp
 	defer cancel()
 
 	// Create session
-	sess, err := NewSession(req.Host, req.Port, req.User, req.Password, req.Prompt, s.timeout)
+	sess, driver, err := newDriverSession(req, s.timeout)
 	if err != nil {
 		return &OLTResponse{
 			Host:    req.Host,
@@ -70,10 +71,10 @@ func (s *Service) ExecuteCommands(ctx context.Context, req OLTRequest) (*OLTResp
 		}, nil
 	}
 
-	// Disable paging
-	_, _ = sess.Exec(totalCtx, "terminal length 0")
-	_, _ = sess.Exec(totalCtx, "screen-length 0 temporary")
-	_, _ = sess.Exec(totalCtx, "disable clipaging")
+	// Disable paging and apply other vendor session setup
+	for _, cmd := range driver.CLI().Setup {
+		_, _ = sess.Exec(totalCtx, cmd)
+	}
 
 	// Execute commands
 	batchOut, err := sess.ExecBatch(totalCtx, req.Commands)
@@ -109,7 +110,7 @@ func (s *Service) ExecuteCommandsWithCustomTimeout(ctx context.Context, req OLTR
 	defer cancel()
 
 	// Create session with custom timeout
-	sess, err := NewSession(req.Host, req.Port, req.User, req.Password, req.Prompt, timeout)
+	sess, driver, err := newDriverSession(req, timeout)
 	if err != nil {
 		return &OLTResponse{
 			Host:    req.Host,
@@ -133,10 +134,10 @@ func (s *Service) ExecuteCommandsWithCustomTimeout(ctx context.Context, req OLTR
 		}, nil
 	}
 
-	// Disable paging
-	_, _ = sess.Exec(totalCtx, "terminal length 0")
-	_, _ = sess.Exec(totalCtx, "screen-length 0 temporary")
-	_, _ = sess.Exec(totalCtx, "disable clipaging")
+	// Disable paging and apply other vendor session setup
+	for _, cmd := range driver.CLI().Setup {
+		_, _ = sess.Exec(totalCtx, cmd)
+	}
 
 	// Execute commands
 	batchOut, err := sess.ExecBatch(totalCtx, req.Commands)
@@ -161,6 +162,29 @@ func (s *Service) ExecuteCommandsWithCustomTimeout(ctx context.Context, req OLTR
 	}, nil
 }
 
+// newDriverSession creates a session using the CLI prompts of the request vendor's driver
+func newDriverSession(req OLTRequest, timeout time.Duration) (*Session, Driver, error) {
+	driver, err := GetDriver(req.Vendor)
+	if err != nil {
+		return nil, nil, err
+	}
+	cli := driver.CLI()
+
+	prompt := req.Prompt
+	if prompt == "" {
+		prompt = cli.Prompt
+	}
+
+	sess, err := NewSession(req.Host, req.Port, req.User, req.Password, prompt, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := sess.SetLoginPrompts(cli.UsernamePrompt, cli.PasswordPrompt); err != nil {
+		return nil, nil, err
+	}
+	return sess, driver, nil
+}
+
 // RenderCommand renders a single command for testing
 func (s *Service) RenderCommand(req OLTRequest) *OLTResponse {
 	return &OLTResponse{
@@ -169,4 +193,4 @@ func (s *Service) RenderCommand(req OLTRequest) *OLTResponse {
 		Success: true,
 		Time:    "0s",
 	}
-}
\ No newline at end of file
+}
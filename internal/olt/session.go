@@ -18,6 +18,8 @@ type Session struct {
 	user          string
 	pass          string
 	promptPattern *regexp.Regexp
+	usernameRE    *regexp.Regexp
+	passwordRE    *regexp.Regexp
 	conn          net.Conn
 	timeout       time.Duration
 	readBuf       bytes.Buffer
@@ -39,10 +41,32 @@ func NewSession(host string, port int, user, pass, promptRegex string, timeout t
 		user:          user,
 		pass:          pass,
 		promptPattern: re,
+		usernameRE:    regexp.MustCompile(`(?i)(username|login)\s*:\s*$`),
+		passwordRE:    regexp.MustCompile(`(?i)password\s*:\s*$`),
 		timeout:       timeout,
 	}, nil
 }
 
+// SetLoginPrompts overrides the username and password prompt patterns; empty
+// patterns keep the defaults
+func (s *Session) SetLoginPrompts(usernameRegex, passwordRegex string) error {
+	if usernameRegex != "" {
+		re, err := regexp.Compile(usernameRegex)
+		if err != nil {
+			return fmt.Errorf("invalid username prompt regex: %w", err)
+		}
+		s.usernameRE = re
+	}
+	if passwordRegex != "" {
+		re, err := regexp.Compile(passwordRegex)
+		if err != nil {
+			return fmt.Errorf("invalid password prompt regex: %w", err)
+		}
+		s.passwordRE = re
+	}
+	return nil
+}
+
 // dial establishes connection to OLT
 func (s *Session) dial() error {
 	c, err := net.DialTimeout("tcp", s.addr, s.timeout)
@@ -136,8 +160,7 @@ func (s *Session) Login(ctx context.Context) (string, error) {
 		return "", err
 	}
 
-	usernameRE := regexp.MustCompile(`(?i)(username|login)\s*:\s*$`)
-	passwordRE := regexp.MustCompile(`(?i)password\s*:\s*$`)
+	usernameRE, passwordRE := s.usernameRE, s.passwordRE
 
 	out1, _ := s.readUntil(ctx, usernameRE, passwordRE, s.promptPattern)
 
@@ -186,4 +209,4 @@ func (s *Session) ExecBatch(ctx context.Context, commands []string) (string, err
 		}
 	}
 	return all.String(), nil
-}
\ No newline at end of file
+}
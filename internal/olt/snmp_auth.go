@@ -0,0 +1,161 @@
+package olt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+var (
+	// ErrSNMPConfig is returned for invalid SNMP version or credential settings
+	ErrSNMPConfig = errors.New("invalid SNMP configuration")
+
+	// ErrSNMPAuth is returned when the OLT rejects SNMPv3 credentials
+	ErrSNMPAuth = errors.New("SNMPv3 authentication failed")
+)
+
+// SNMPv3Credentials holds the USM credentials of an SNMPv3 request
+type SNMPv3Credentials struct {
+	Username      string
+	SecurityLevel string // noAuthNoPriv, authNoPriv or authPriv (default: derived from the passwords)
+	AuthProtocol  string // MD5, SHA, SHA224, SHA256, SHA384, SHA512 (default: SHA)
+	AuthPassword  string
+	PrivProtocol  string // DES, AES, AES192, AES256, AES192C, AES256C (default: AES)
+	PrivPassword  string
+	ContextName   string
+}
+
+// sysObjectIDOID is queried to verify SNMPv3 credentials before the real queries
+const sysObjectIDOID = ".1.3.6.1.2.1.1.2.0"
+
+var snmpAuthProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+	"MD5":    gosnmp.MD5,
+	"SHA":    gosnmp.SHA,
+	"SHA1":   gosnmp.SHA,
+	"SHA224": gosnmp.SHA224,
+	"SHA256": gosnmp.SHA256,
+	"SHA384": gosnmp.SHA384,
+	"SHA512": gosnmp.SHA512,
+}
+
+var snmpPrivProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+	"DES":     gosnmp.DES,
+	"AES":     gosnmp.AES,
+	"AES128":  gosnmp.AES,
+	"AES192":  gosnmp.AES192,
+	"AES256":  gosnmp.AES256,
+	"AES192C": gosnmp.AES192C,
+	"AES256C": gosnmp.AES256C,
+}
+
+// applySNMPVersion configures the version and credentials of a connection
+func applySNMPVersion(snmp *gosnmp.GoSNMP, version, community string, v3 *SNMPv3Credentials) error {
+	switch strings.TrimPrefix(strings.ToLower(version), "v") {
+	case "", "2c", "2":
+		snmp.Version = gosnmp.Version2c
+	case "1":
+		snmp.Version = gosnmp.Version1
+	case "3":
+		return applySNMPv3(snmp, v3)
+	default:
+		return fmt.Errorf("%w: unsupported SNMP version %q (supported: 1, 2c, 3)", ErrSNMPConfig, version)
+	}
+
+	if community == "" {
+		return fmt.Errorf("%w: SNMPv%s requires a community", ErrSNMPConfig, snmp.Version)
+	}
+	snmp.Community = community
+	return nil
+}
+
+// applySNMPv3 configures the USM security parameters of an SNMPv3 connection
+func applySNMPv3(snmp *gosnmp.GoSNMP, v3 *SNMPv3Credentials) error {
+	if v3 == nil || v3.Username == "" {
+		return fmt.Errorf("%w: SNMPv3 requires a username", ErrSNMPConfig)
+	}
+
+	level := strings.ToLower(v3.SecurityLevel)
+	if level == "" {
+		switch {
+		case v3.PrivPassword != "":
+			level = "authpriv"
+		case v3.AuthPassword != "":
+			level = "authnopriv"
+		default:
+			level = "noauthnopriv"
+		}
+	}
+
+	usm := &gosnmp.UsmSecurityParameters{UserName: v3.Username}
+	var flags gosnmp.SnmpV3MsgFlags
+	switch level {
+	case "noauthnopriv":
+		flags = gosnmp.NoAuthNoPriv
+	case "authnopriv", "authpriv":
+		auth, ok := snmpAuthProtocols[strings.ToUpper(defaultString(v3.AuthProtocol, "SHA"))]
+		if !ok {
+			return fmt.Errorf("%w: unsupported auth protocol %q (supported: MD5, SHA, SHA224, SHA256, SHA384, SHA512)", ErrSNMPConfig, v3.AuthProtocol)
+		}
+		if len(v3.AuthPassword) < 8 {
+			return fmt.Errorf("%w: auth password must be at least 8 characters", ErrSNMPConfig)
+		}
+		usm.AuthenticationProtocol = auth
+		usm.AuthenticationPassphrase = v3.AuthPassword
+		flags = gosnmp.AuthNoPriv
+
+		if level == "authpriv" {
+			priv, ok := snmpPrivProtocols[strings.ToUpper(defaultString(v3.PrivProtocol, "AES"))]
+			if !ok {
+				return fmt.Errorf("%w: unsupported privacy protocol %q (supported: DES, AES, AES192, AES256, AES192C, AES256C)", ErrSNMPConfig, v3.PrivProtocol)
+			}
+			if len(v3.PrivPassword) < 8 {
+				return fmt.Errorf("%w: privacy password must be at least 8 characters", ErrSNMPConfig)
+			}
+			usm.PrivacyProtocol = priv
+			usm.PrivacyPassphrase = v3.PrivPassword
+			flags = gosnmp.AuthPriv
+		}
+	default:
+		return fmt.Errorf("%w: unsupported security level %q (supported: noAuthNoPriv, authNoPriv, authPriv)", ErrSNMPConfig, v3.SecurityLevel)
+	}
+
+	snmp.Version = gosnmp.Version3
+	snmp.SecurityModel = gosnmp.UserSecurityModel
+	snmp.MsgFlags = flags
+	snmp.SecurityParameters = usm
+	snmp.ContextName = v3.ContextName
+	return nil
+}
+
+// snmpAuthError turns the USM report errors of gosnmp into ErrSNMPAuth with
+// a hint at the likely cause; other errors are returned unchanged
+func snmpAuthError(err error) error {
+	var hint string
+	switch {
+	case errors.Is(err, gosnmp.ErrUnknownUsername):
+		hint = "unknown username"
+	case errors.Is(err, gosnmp.ErrWrongDigest):
+		hint = "wrong digest, check the auth password and protocol"
+	case errors.Is(err, gosnmp.ErrDecryption):
+		hint = "decryption error, check the privacy password and protocol"
+	case errors.Is(err, gosnmp.ErrUnknownSecurityLevel):
+		hint = "security level not supported for this user"
+	case errors.Is(err, gosnmp.ErrNotInTimeWindow):
+		hint = "not in time window"
+	case errors.Is(err, gosnmp.ErrUnknownEngineID):
+		hint = "unknown engine ID"
+	default:
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrSNMPAuth, hint)
+}
+
+// defaultString returns s, or def when s is empty
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,232 @@
+package olt
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// ifAdminStatusOID is the IF-MIB ifAdminStatus column
+const ifAdminStatusOID = ".1.3.6.1.2.1.2.2.1.7"
+
+// ifStatusNames names the IF-MIB ifAdminStatus/ifOperStatus values
+var ifStatusNames = map[int]string{
+	1: "up",
+	2: "down",
+	3: "testing",
+	4: "unknown",
+	5: "dormant",
+	6: "notPresent",
+	7: "lowerLayerDown",
+}
+
+// PONDiagnostics represents the optical module readings and state of a PON
+// port; readings the OLT does not report are nil
+type PONDiagnostics struct {
+	Rack        int
+	Shelf       int
+	Slot        int
+	Port        int
+	Interface   string
+	AdminStatus string // IF-MIB ifAdminStatus, empty when unknown
+	OperStatus  string // IF-MIB ifOperStatus, empty when unknown
+	TotalONUs   int
+	TxPower     *float64 // dBm
+	Temperature *float64 // °C
+	BiasCurrent *float64 // mA
+	Voltage     *float64 // V
+	Error       string   // set when the port could not be read
+}
+
+// OLTDiagnostics represents the optical module readings of every PON port of an OLT
+type OLTDiagnostics struct {
+	Host          string
+	TotalPorts    int
+	FailedPorts   int
+	Ports         []PONDiagnostics
+	ExecutionTime string
+	Timestamp     time.Time
+}
+
+// GetPONDiagnostics retrieves the optical module readings, status and ONU
+// count of a PON port
+func (s *SNMPService) GetPONDiagnostics(ctx context.Context, req SNMPRequest) (*PONDiagnostics, error) {
+	snmp, err := s.setupSNMPConnection(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup SNMP connection: %w", err)
+	}
+	defer snmp.Conn.Close()
+
+	oltConfig, err := s.getOltConfig(snmp, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OLT config: %w", err)
+	}
+
+	diagnostics, err := readPortDiagnostics(snmp, oltConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &diagnostics, nil
+}
+
+// GetOLTDiagnostics retrieves the optical module readings of every PON port
+// of an OLT, including ports that are down, over up to concurrency SNMP
+// sessions; a port that cannot be read is reported with its error
+func (s *SNMPService) GetOLTDiagnostics(ctx context.Context, req SNMPRequest, concurrency int) (*OLTDiagnostics, error) {
+	startTime := time.Now()
+
+	driver, err := GetDriver(req.Vendor)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := SelectOIDProfile(req.Vendor, req.Model, req.Firmware, req.OIDProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	ports, err := s.discoverPorts(req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OLTDiagnostics{
+		Host:       req.Host,
+		TotalPorts: len(ports),
+		Ports:      make([]PONDiagnostics, len(ports)),
+	}
+	s.readPorts(ctx, req, len(ports), concurrency, func(conn *gosnmp.GoSNMP, i int, err error) {
+		var oltConfig *OltConfig
+		if err == nil {
+			oltConfig, err = buildOltConfig(driver, profile, ports[i])
+		}
+		if err == nil {
+			result.Ports[i], err = readPortDiagnostics(conn, oltConfig)
+		}
+		if err != nil {
+			result.Ports[i] = portDiagnostics(ports[i])
+			result.Ports[i].Error = err.Error()
+		}
+	})
+
+	for _, port := range result.Ports {
+		if port.Error != "" {
+			result.FailedPorts++
+		}
+	}
+
+	result.ExecutionTime = fmt.Sprintf("%.2fs", time.Since(startTime).Seconds())
+	result.Timestamp = time.Now()
+	return result, nil
+}
+
+// readPortDiagnostics reads the optical module readings and IF-MIB status of
+// a PON port with one GET, and counts its ONUs
+func readPortDiagnostics(snmp *gosnmp.GoSNMP, config *OltConfig) (PONDiagnostics, error) {
+	result := portDiagnostics(config.Port)
+
+	readings := map[string]struct {
+		value  **float64
+		column string
+	}{
+		config.PonTxPowerOID:     {&result.TxPower, "pon_tx_power"},
+		config.PonTemperatureOID: {&result.Temperature, "pon_temperature"},
+		config.PonBiasCurrentOID: {&result.BiasCurrent, "pon_bias_current"},
+		config.PonVoltageOID:     {&result.Voltage, "pon_voltage"},
+	}
+	delete(readings, "")
+
+	var oids []string
+	for oid := range readings {
+		oids = append(oids, oid)
+	}
+	adminOID := fmt.Sprintf("%s.%d", ifAdminStatusOID, config.Port.IfIndex)
+	operOID := fmt.Sprintf("%s.%d", ifOperStatusOID, config.Port.IfIndex)
+	if config.Port.IfIndex > 0 {
+		oids = append(oids, adminOID, operOID)
+	}
+
+	if len(oids) > 0 {
+		response, err := snmp.Get(oids)
+		if err != nil {
+			return result, fmt.Errorf("SNMP get failed: %w", snmpAuthError(err))
+		}
+		for _, pdu := range response.Variables {
+			name := "." + strings.TrimPrefix(pdu.Name, ".")
+			switch name {
+			case adminOID:
+				result.AdminStatus = ifStatusName(pdu)
+			case operOID:
+				result.OperStatus = ifStatusName(pdu)
+			default:
+				if r, ok := readings[name]; ok {
+					*r.value = numericReading(pdu, config.Scales[r.column])
+				}
+			}
+		}
+	}
+
+	err := walkColumn(snmp, config.OnuIDNameOID, func(pdu gosnmp.SnmpPDU) error {
+		if _, ok := columnIndex(config.OnuIDNameOID, pdu.Name); ok {
+			result.TotalONUs++
+		}
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("SNMP walk failed: %w", snmpAuthError(err))
+	}
+	return result, nil
+}
+
+// portDiagnostics returns the diagnostics entry of a port without readings
+func portDiagnostics(port PONPort) PONDiagnostics {
+	return PONDiagnostics{
+		Rack:      port.Rack,
+		Shelf:     port.Shelf,
+		Slot:      port.Slot,
+		Port:      port.Port,
+		Interface: port.Name,
+	}
+}
+
+// ifStatusName returns the name of an IF-MIB status value
+func ifStatusName(pdu gosnmp.SnmpPDU) string {
+	if v, ok := pdu.Value.(int); ok {
+		return ifStatusNames[v]
+	}
+	return ""
+}
+
+// numericReading returns a numeric reading scaled into its unit, or nil when the
+// OLT has no value: no such instance, or a sentinel such as 0x7FFFFFFF for a
+// missing module. Some firmwares report readings as strings ("2.35").
+func numericReading(pdu gosnmp.SnmpPDU, scale float64) *float64 {
+	var v float64
+	switch pdu.Type {
+	case gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView, gosnmp.Null:
+		return nil
+	case gosnmp.OctetString:
+		b, _ := pdu.Value.([]byte)
+		f, err := strconv.ParseFloat(strings.TrimSpace(string(b)), 64)
+		if err != nil {
+			return nil
+		}
+		v = f
+	default:
+		n := gosnmp.ToBigInt(pdu.Value)
+		if !n.IsInt64() || n.Int64() >= math.MaxInt32 || n.Int64() <= math.MinInt32 {
+			return nil
+		}
+		v = float64(n.Int64())
+	}
+
+	if scale > 0 {
+		v *= scale
+	}
+	v = math.Round(v*1000) / 1000
+	return &v
+}
@@ -0,0 +1,179 @@
+package olt
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// ErrPONNotFound is returned when the OLT does not have the requested PON port
+var ErrPONNotFound = errors.New("PON port not found")
+
+// ErrONUNotFound is returned when the PON port has no ONU with the requested ID
+var ErrONUNotFound = errors.New("ONU not found")
+
+// PONPort identifies a PON port of an OLT for SNMP queries
+type PONPort struct {
+	Rack    int
+	Shelf   int
+	Slot    int
+	Port    int
+	IfIndex int    // ifIndex discovered from ifDescr, 0 when unknown
+	Name    string // ifDescr name, e.g. gpon_1/2/1
+}
+
+// String returns the port as rack/shelf/slot/port
+func (p PONPort) String() string {
+	return fmt.Sprintf("%d/%d/%d/%d", p.Rack, p.Shelf, p.Slot, p.Port)
+}
+
+// ifDescrOID is the IF-MIB ifDescr column
+const ifDescrOID = ".1.3.6.1.2.1.2.2.1.2"
+
+// ifIndexCacheTTL is how long the discovered PON ports of an OLT are reused
+const ifIndexCacheTTL = time.Hour
+
+// ponIfDescrRE matches PON port names in ifDescr: gpon_1/2/1 and gpon-olt_1/2/1
+// (C300), gpon_olt-1/2/1 (C600), xgs-pon/epon variants and 4-number forms
+// with a rack. ONU interfaces do not match.
+var ponIfDescrRE = regexp.MustCompile(`(?i)^[a-z0-9-]*pon(?:[-_]olt)?[-_](\d+(?:/\d+){2,3})$`)
+
+// ifIndexEntry holds the PON ports discovered on an OLT
+type ifIndexEntry struct {
+	ports   map[string]PONPort // by rack/shelf/slot/port
+	expires time.Time
+}
+
+// ifIndexCache caches discovered PON port ifIndexes per OLT
+type ifIndexCache struct {
+	mu   sync.Mutex
+	olts map[string]ifIndexEntry
+}
+
+var ponIfIndexes = &ifIndexCache{olts: make(map[string]ifIndexEntry)}
+
+// get returns the cached PON ports of an OLT
+func (c *ifIndexCache) get(olt string) (map[string]PONPort, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.olts[olt]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.ports, true
+}
+
+// set stores the PON ports of an OLT
+func (c *ifIndexCache) set(olt string, ports map[string]PONPort) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.olts[olt] = ifIndexEntry{ports: ports, expires: time.Now().Add(ifIndexCacheTTL)}
+}
+
+// parsePONIfDescr returns the PON port named by an ifDescr value
+func parsePONIfDescr(descr string) (PONPort, bool) {
+	m := ponIfDescrRE.FindStringSubmatch(strings.TrimSpace(descr))
+	if m == nil {
+		return PONPort{}, false
+	}
+
+	var nums []int
+	for _, part := range strings.Split(m[1], "/") {
+		n, _ := strconv.Atoi(part)
+		nums = append(nums, n)
+	}
+	if len(nums) == 3 {
+		nums = append([]int{1}, nums...)
+	}
+	return PONPort{Rack: nums[0], Shelf: nums[1], Slot: nums[2], Port: nums[3], Name: m[0]}, true
+}
+
+// discoverPONPorts walks ifDescr and returns the PON ports of an OLT with
+// their ifIndex, keyed by rack/shelf/slot/port
+func discoverPONPorts(snmp *gosnmp.GoSNMP) (map[string]PONPort, error) {
+	ports := make(map[string]PONPort)
+	err := walkColumn(snmp, ifDescrOID, func(pdu gosnmp.SnmpPDU) error {
+		value, ok := pdu.Value.([]byte)
+		if !ok {
+			return nil
+		}
+		port, ok := parsePONIfDescr(string(value))
+		if !ok {
+			return nil
+		}
+		index, err := strconv.Atoi(pdu.Name[strings.LastIndex(pdu.Name, ".")+1:])
+		if err != nil {
+			return nil
+		}
+		port.IfIndex = index
+		ports[port.String()] = port
+		return nil
+	})
+	return ports, err
+}
+
+// resolvePONPort sets the ifIndex of a PON port from the ports discovered on
+// the OLT, walking ifDescr when they are not cached or do not include the port
+// (e.g. a card inserted since). When the OLT lists no PON ports in ifDescr the
+// ifIndex stays 0 and the driver computes it.
+func resolvePONPort(snmp *gosnmp.GoSNMP, port PONPort) (PONPort, error) {
+	ports, cached := ponIfIndexes.get(oltKey(snmp))
+	if found, ok := ports[port.String()]; ok {
+		return found, nil
+	}
+
+	if !cached || len(ports) > 0 {
+		var err error
+		if ports, err = refreshPONPorts(snmp); err != nil {
+			return port, err
+		}
+	}
+
+	if len(ports) == 0 {
+		return port, nil
+	}
+	found, ok := ports[port.String()]
+	if !ok {
+		return port, fmt.Errorf("%w: %s", ErrPONNotFound, port)
+	}
+	return found, nil
+}
+
+// refreshPONPorts discovers the PON ports of an OLT and caches them
+func refreshPONPorts(snmp *gosnmp.GoSNMP) (map[string]PONPort, error) {
+	ports, err := discoverPONPorts(snmp)
+	if err != nil {
+		return nil, fmt.Errorf("ifDescr discovery failed: %w", snmpAuthError(err))
+	}
+	ponIfIndexes.set(oltKey(snmp), ports)
+	return ports, nil
+}
+
+// oltKey identifies the OLT of a connection in the ifIndex cache
+func oltKey(snmp *gosnmp.GoSNMP) string {
+	return fmt.Sprintf("%s:%d", snmp.Target, snmp.Port)
+}
+
+// orOne returns n, or 1 when n is unset
+func orOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// walkColumn walks an OID subtree, with GETBULK except on SNMPv1
+func walkColumn(snmp *gosnmp.GoSNMP, oid string, fn gosnmp.WalkFunc) error {
+	if snmp.Version == gosnmp.Version1 {
+		return snmp.Walk(oid, fn)
+	}
+	return snmp.BulkWalk(oid, fn)
+}
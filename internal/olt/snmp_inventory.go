@@ -0,0 +1,300 @@
+package olt
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+const (
+	// DefaultInventoryConcurrency is the number of PON ports of an OLT read at once
+	DefaultInventoryConcurrency = 4
+
+	// MaxInventoryConcurrency caps the SNMP sessions opened on one OLT
+	MaxInventoryConcurrency = 16
+)
+
+// ifOperStatusOID is the IF-MIB ifOperStatus column (1 = up)
+const ifOperStatusOID = ".1.3.6.1.2.1.2.2.1.8"
+
+// offlineStatuses are the ONU statuses counted as offline; the others than
+// Online (Logging, Synchronization) are an ONU coming up
+var offlineStatuses = []string{"LOS", "Dying Gasp", "Auth Failed", "Offline", "Unknown"}
+
+// IsOfflineStatus reports whether an ONU status is an offline state
+func IsOfflineStatus(status string) bool {
+	return slices.Contains(offlineStatuses, status)
+}
+
+// PONInventory represents the ONUs of one PON port of an OLT; ONUs coming up
+// are counted neither online nor offline
+type PONInventory struct {
+	Rack          int
+	Shelf         int
+	Slot          int
+	Port          int
+	Interface     string
+	TotalONUs     int
+	Online        int
+	Offline       int
+	ONUs          []SNMPONUInfo
+	ExecutionTime string
+	Error         string // set when the port could not be read
+}
+
+// OLTInventory represents the ONUs of every active PON port of an OLT
+type OLTInventory struct {
+	Host          string
+	OIDProfile    string
+	TotalPorts    int
+	FailedPorts   int
+	TotalONUs     int
+	Online        int
+	Offline       int
+	ByStatus      map[string]int
+	Ports         []PONInventory
+	ExecutionTime string
+	Timestamp     time.Time
+}
+
+// GetOLTInventory retrieves the ONUs of every active PON port of an OLT. Ports
+// are discovered from ifDescr and read by up to concurrency SNMP sessions at
+// once; a port that cannot be read is reported with its error.
+func (s *SNMPService) GetOLTInventory(ctx context.Context, req SNMPRequest, concurrency int) (*OLTInventory, error) {
+	return s.inventory(ctx, req, concurrency, true)
+}
+
+// GetOfflineInventory is GetOLTInventory over every PON port, including those
+// that are down, whose ONUs are all offline
+func (s *SNMPService) GetOfflineInventory(ctx context.Context, req SNMPRequest, concurrency int) (*OLTInventory, error) {
+	return s.inventory(ctx, req, concurrency, false)
+}
+
+// inventory reads the ONUs of the PON ports of an OLT; activeOnly skips the
+// ports that are not up
+func (s *SNMPService) inventory(ctx context.Context, req SNMPRequest, concurrency int, activeOnly bool) (*OLTInventory, error) {
+	startTime := time.Now()
+
+	driver, err := GetDriver(req.Vendor)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := SelectOIDProfile(req.Vendor, req.Model, req.Firmware, req.OIDProfile)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := oltLocation(req.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	ports, err := s.discoverPorts(req, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &OLTInventory{
+		Host:       req.Host,
+		OIDProfile: profile.Name,
+		TotalPorts: len(ports),
+		ByStatus:   make(map[string]int),
+		Ports:      make([]PONInventory, len(ports)),
+	}
+
+	s.readPorts(ctx, req, len(ports), concurrency, func(conn *gosnmp.GoSNMP, i int, err error) {
+		if err != nil {
+			inventory.Ports[i] = portInventory(ports[i], err)
+			return
+		}
+		inventory.Ports[i] = s.readPONPort(conn, driver, profile, ports[i], loc)
+	})
+
+	for _, port := range inventory.Ports {
+		if port.Error != "" {
+			inventory.FailedPorts++
+		}
+		inventory.TotalONUs += port.TotalONUs
+		inventory.Online += port.Online
+		inventory.Offline += port.Offline
+		for _, onu := range port.ONUs {
+			inventory.ByStatus[onu.Status]++
+		}
+	}
+
+	inventory.ExecutionTime = fmt.Sprintf("%.2fs", time.Since(startTime).Seconds())
+	inventory.Timestamp = time.Now()
+	return inventory, nil
+}
+
+// readPorts calls read for ports 0..n-1 over up to concurrency SNMP sessions
+// to the OLT, one port at a time per session. err is set instead of the
+// session when it could not be opened or the request was cancelled.
+func (s *SNMPService) readPorts(ctx context.Context, req SNMPRequest, n, concurrency int, read func(conn *gosnmp.GoSNMP, i int, err error)) {
+	if concurrency < 1 {
+		concurrency = DefaultInventoryConcurrency
+	}
+	concurrency = min(concurrency, MaxInventoryConcurrency)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			conn, err := s.setupSNMPConnection(req)
+			if err == nil {
+				defer conn.Conn.Close()
+			} else {
+				err = fmt.Errorf("failed to setup SNMP connection: %w", err)
+			}
+			for i := range jobs {
+				switch {
+				case err != nil:
+					read(nil, i, err)
+				case ctx.Err() != nil:
+					read(nil, i, ctx.Err())
+				default:
+					read(conn, i, nil)
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// discoverPorts discovers the PON ports of an OLT afresh, as cards may have
+// changed since the last run, sorted by rack/shelf/slot/port; activeOnly
+// keeps those that are up
+func (s *SNMPService) discoverPorts(req SNMPRequest, activeOnly bool) ([]PONPort, error) {
+	snmp, err := s.setupSNMPConnection(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup SNMP connection: %w", err)
+	}
+	defer snmp.Conn.Close()
+
+	discovered, err := refreshPONPorts(snmp)
+	if err != nil {
+		return nil, err
+	}
+	if len(discovered) == 0 {
+		return nil, fmt.Errorf("%w: the OLT lists no PON ports in ifDescr", ErrPONNotFound)
+	}
+	if activeOnly {
+		return activePONPorts(snmp, discovered), nil
+	}
+
+	ports := make([]PONPort, 0, len(discovered))
+	for _, port := range discovered {
+		ports = append(ports, port)
+	}
+	sortPONPorts(ports)
+	return ports, nil
+}
+
+// readPONPort reads the ONUs of one PON port of an OLT whose clock is in loc
+func (s *SNMPService) readPONPort(snmp *gosnmp.GoSNMP, driver Driver, profile OIDProfile, port PONPort, loc *time.Location) PONInventory {
+	startTime := time.Now()
+
+	oltConfig, err := buildOltConfig(driver, profile, port)
+	if err != nil {
+		return portInventory(port, err)
+	}
+	oltConfig.Location = loc
+	onus, err := s.listONUs(snmp, oltConfig, port.Slot, port.Port)
+	if err != nil {
+		return portInventory(port, err)
+	}
+
+	result := portInventory(port, nil)
+	result.TotalONUs = len(onus)
+	result.ONUs = onus
+	for _, onu := range onus {
+		switch {
+		case onu.Status == "Online":
+			result.Online++
+		case IsOfflineStatus(onu.Status):
+			result.Offline++
+		}
+	}
+	result.ExecutionTime = fmt.Sprintf("%.2fs", time.Since(startTime).Seconds())
+	return result
+}
+
+// portInventory returns the inventory entry of a port, failed with err when not nil
+func portInventory(port PONPort, err error) PONInventory {
+	result := PONInventory{
+		Rack:      port.Rack,
+		Shelf:     port.Shelf,
+		Slot:      port.Slot,
+		Port:      port.Port,
+		Interface: port.Name,
+		ONUs:      []SNMPONUInfo{},
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// activePONPorts returns the ports whose ifOperStatus is up, sorted. All
+// ports are kept when the OLT reports no status for them.
+func activePONPorts(snmp *gosnmp.GoSNMP, ports map[string]PONPort) []PONPort {
+	status := make(map[int]int)
+	_ = walkColumn(snmp, ifOperStatusOID, func(pdu gosnmp.SnmpPDU) error {
+		if id, ok := columnIndex(ifOperStatusOID, pdu.Name); ok {
+			if v, ok := pdu.Value.(int); ok {
+				status[id] = v
+			}
+		}
+		return nil
+	})
+
+	var all, up []PONPort
+	for _, port := range ports {
+		all = append(all, port)
+		if status[port.IfIndex] == 1 {
+			up = append(up, port)
+		}
+	}
+
+	known := false
+	for _, port := range all {
+		if _, ok := status[port.IfIndex]; ok {
+			known = true
+			break
+		}
+	}
+	if !known {
+		up = all
+	}
+
+	sortPONPorts(up)
+	return up
+}
+
+// sortPONPorts sorts ports by rack/shelf/slot/port
+func sortPONPorts(ports []PONPort) {
+	sort.Slice(ports, func(i, j int) bool {
+		a, b := ports[i], ports[j]
+		if a.Rack != b.Rack {
+			return a.Rack < b.Rack
+		}
+		if a.Shelf != b.Shelf {
+			return a.Shelf < b.Shelf
+		}
+		if a.Slot != b.Slot {
+			return a.Slot < b.Slot
+		}
+		return a.Port < b.Port
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gosnmp/gosnmp"
@@ -12,12 +13,25 @@ import (
 
 // SNMPRequest represents SNMP request parameters
 type SNMPRequest struct {
-	Host      string
-	Port      int
-	Community string
-	BoardID   int
-	PONID     int
-	Timeout   int
+	Host       string
+	Port       int
+	Community  string
+	Version    string             // "1", "2c" or "3" (default: 2c)
+	V3         *SNMPv3Credentials // required for version 3
+	Vendor     string
+	Model      string // selects the OID profile with Firmware
+	Firmware   string
+	OIDProfile string // OID profile name, overriding the model/firmware selection
+	TimeZone   string // IANA time zone of the OLT clock (default: the server's)
+	Rack       int    // default: 1
+	Shelf      int    // default: 1
+	BoardID    int
+	PONID      int
+	Timeout    int
+
+	// MaxRepetitions is the GETBULK max-repetitions of column walks
+	// (default: 50); lower it for OLTs that drop large responses
+	MaxRepetitions int
 }
 
 // SNMPONUInfo represents ONU information from SNMP
@@ -36,6 +50,13 @@ type SNMPONUInfo struct {
 	LastOnline          string `json:"last_online"`
 	Uptime              string `json:"uptime"`
 	GponOpticalDistance string `json:"gpon_optical_distance"`
+	LastOffline         string `json:"last_offline"`
+	LastOfflineReason   string `json:"last_offline_reason"`
+
+	// StateDuration is how long the ONU has been in its current state: since
+	// it last came online when online, otherwise since it last went offline
+	StateDuration        string `json:"state_duration"`
+	StateDurationSeconds int64  `json:"state_duration_seconds"`
 }
 
 // SNMPResult represents the result of SNMP query
@@ -49,9 +70,12 @@ type SNMPResult struct {
 	Timestamp     time.Time
 }
 
-// OltConfig represents OLT configuration for specific board and PON
+// OltConfig holds the ONU table column OIDs of a PON port, built from an
+// OID profile; rows append the ONU ID (and ".1" for rx/tx power and IP address,
+// the gemport for traffic counters where the OLT counts per gemport)
 type OltConfig struct {
-	BaseOID                   string
+	Profile                   string  // name of the OID profile
+	Port                      PONPort // PON port the OIDs are built for
 	OnuIDNameOID              string
 	OnuTypeOID                string
 	OnuSerialNumberOID        string
@@ -64,6 +88,17 @@ type OltConfig struct {
 	OnuLastOfflineOID         string
 	OnuLastOfflineReasonOID   string
 	OnuGponOpticalDistanceOID string
+	OnuUpstreamOctetsOID      string
+	OnuDownstreamOctetsOID    string
+
+	// PON port optical module readings, read as is
+	PonTxPowerOID     string
+	PonTemperatureOID string
+	PonBiasCurrentOID string
+	PonVoltageOID     string
+
+	Scales   map[string]float64 // value multipliers by profile column
+	Location *time.Location     // time zone of the OLT clock, for its date columns
 }
 
 // SNMPService represents SNMP service for OLT monitoring
@@ -82,78 +117,24 @@ func NewFinalSNMPService(timeout time.Duration) *SNMPService {
 func (s *SNMPService) GetONUByBoardAndPON(ctx context.Context, req SNMPRequest) (*SNMPResult, error) {
 	startTime := time.Now()
 
-	// Get OLT configuration
-	oltConfig, err := s.getOltConfig(req.BoardID, req.PONID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get OLT config: %w", err)
-	}
-
 	// Setup SNMP connection
-	snmp, err := s.setupSNMPConnection(req.Host, req.Port, req.Community)
+	snmp, err := s.setupSNMPConnection(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup SNMP connection: %w", err)
 	}
 	defer snmp.Conn.Close()
 
-	// SNMP Walk to get ONU ID and Name
-	snmpDataMap := make(map[string]gosnmp.SnmpPDU)
-	err = snmp.Walk(oltConfig.BaseOID+oltConfig.OnuIDNameOID, func(pdu gosnmp.SnmpPDU) error {
-		snmpDataMap[ExtractONUID(pdu.Name)] = pdu
-		return nil
-	})
-
+	// Get OLT configuration
+	oltConfig, err := s.getOltConfig(snmp, req)
 	if err != nil {
-		return nil, fmt.Errorf("SNMP walk failed: %w", err)
+		return nil, fmt.Errorf("failed to get OLT config: %w", err)
 	}
 
-	var onuInformationList []SNMPONUInfo
-
-	// Process SNMP data to get ONU information
-	for _, pdu := range snmpDataMap {
-		onuInfo := SNMPONUInfo{
-			Board: req.BoardID,
-			PON:   req.PONID,
-			ID:    ExtractIDOnuID(pdu.Name),
-			Name:  ExtractName(pdu.Value),
-		}
-
-		// Get ONU type (like "F609V5.3", "F660V7.0", etc.)
-		if onuType, err := s.getONUType(snmp, oltConfig, strconv.Itoa(onuInfo.ID)); err == nil {
-			onuInfo.OnuType = onuType
-		}
-
-		if description, err := s.getONUDescription(snmp, oltConfig, strconv.Itoa(onuInfo.ID)); err == nil {
-			onuInfo.Description = description
-		}
-
-		if sn, err := s.getSerialNumber(snmp, oltConfig, strconv.Itoa(onuInfo.ID)); err == nil {
-			onuInfo.SerialNumber = sn
-		}
-
-		if rx, err := s.getRxPower(snmp, oltConfig, strconv.Itoa(onuInfo.ID)); err == nil {
-			onuInfo.RXPower = rx
-		}
-
-		if tx, err := s.getTxPower(snmp, oltConfig, strconv.Itoa(onuInfo.ID)); err == nil {
-			onuInfo.TXPower = tx
-		}
-
-		if status, err := s.getStatus(snmp, oltConfig, strconv.Itoa(onuInfo.ID)); err == nil {
-			onuInfo.Status = status
-		}
-
-		if ip, err := s.getIPAddress(snmp, oltConfig, strconv.Itoa(onuInfo.ID)); err == nil {
-			onuInfo.IPAddress = ip
-		}
-
-		onuInformationList = append(onuInformationList, onuInfo)
+	onuInformationList, err := s.listONUs(snmp, oltConfig, req.BoardID, req.PONID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Sort ONU information by ID
-	sort.Slice(onuInformationList, func(i, j int) bool {
-		return onuInformationList[i].ID < onuInformationList[j].ID
-	})
-
 	// Prepare result
 	result := &SNMPResult{
 		Host:          req.Host,
@@ -171,19 +152,19 @@ func (s *SNMPService) GetONUByBoardAndPON(ctx context.Context, req SNMPRequest)
 // GetONUDetails retrieves specific ONU details by board, PON, and ONU ID
 func (s *SNMPService) GetONUDetails(ctx context.Context, req SNMPRequest, onuID int) (*SNMPONUInfo, error) {
 
-	// Get OLT configuration
-	oltConfig, err := s.getOltConfig(req.BoardID, req.PONID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get OLT config: %w", err)
-	}
-
 	// Setup SNMP connection
-	snmp, err := s.setupSNMPConnection(req.Host, req.Port, req.Community)
+	snmp, err := s.setupSNMPConnection(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup SNMP connection: %w", err)
 	}
 	defer snmp.Conn.Close()
 
+	// Get OLT configuration
+	oltConfig, err := s.getOltConfig(snmp, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OLT config: %w", err)
+	}
+
 	// Create ONU info with basic info first
 	onuInfo := SNMPONUInfo{
 		Board: req.BoardID,
@@ -237,28 +218,43 @@ func (s *SNMPService) GetONUDetails(ctx context.Context, req SNMPRequest, onuID
 	// Get last online
 	if lastOnline, err := s.getLastOnline(snmp, oltConfig, onuIDStr); err == nil {
 		onuInfo.LastOnline = lastOnline
-		if uptime, err := s.calculateUptime(lastOnline); err == nil {
+		if uptime, err := s.calculateUptime(lastOnline, oltConfig.Location); err == nil {
 			onuInfo.Uptime = uptime
 		}
 	}
 
+	// Get last offline and its reason
+	if lastOffline, err := s.getLastOffline(snmp, oltConfig, onuIDStr); err == nil {
+		onuInfo.LastOffline = lastOffline
+	}
+	if reason, err := s.getLastOfflineReason(snmp, oltConfig, onuIDStr); err == nil {
+		onuInfo.LastOfflineReason = reason
+	}
+
 	// Get optical distance
 	if distance, err := s.getOpticalDistance(snmp, oltConfig, onuIDStr); err == nil {
 		onuInfo.GponOpticalDistance = distance
 	}
 
+	onuInfo.setStateDuration(time.Now(), oltConfig.Location)
 	return &onuInfo, nil
 }
 
-// setupSNMPConnection sets up SNMP connection
-func (s *SNMPService) setupSNMPConnection(host string, port int, community string) (*gosnmp.GoSNMP, error) {
+// setupSNMPConnection sets up SNMP connection. SNMPv3 credentials are checked
+// with a sysObjectID query so that authentication failures are reported as
+// ErrSNMPAuth instead of empty results.
+func (s *SNMPService) setupSNMPConnection(req SNMPRequest) (*gosnmp.GoSNMP, error) {
 	snmp := &gosnmp.GoSNMP{
-		Target:    host,
-		Port:      uint16(port),
-		Community: community,
-		Version:   gosnmp.Version2c,
-		Timeout:   s.timeout,
-		Retries:   3,
+		Target:  req.Host,
+		Port:    uint16(req.Port),
+		Timeout: s.timeout,
+		Retries: 3,
+	}
+	if req.MaxRepetitions > 0 {
+		snmp.MaxRepetitions = uint32(req.MaxRepetitions)
+	}
+	if err := applySNMPVersion(snmp, req.Version, req.Community, req.V3); err != nil {
+		return nil, err
 	}
 
 	err := snmp.Connect()
@@ -266,73 +262,234 @@ func (s *SNMPService) setupSNMPConnection(host string, port int, community strin
 		return nil, err
 	}
 
+	if snmp.Version == gosnmp.Version3 {
+		if _, err := snmp.Get([]string{sysObjectIDOID}); err != nil {
+			snmp.Conn.Close()
+			return nil, snmpAuthError(err)
+		}
+	}
+
 	return snmp, nil
 }
 
-// getOltConfig gets OLT configuration based on board and PON ID
-func (s *SNMPService) getOltConfig(boardID, ponID int) (*OltConfig, error) {
-	// Base OIDs from working configuration
-	baseOID1 := ".1.3.6.1.4.1.3902.1082"
-
-	// Calculate interface index based on board and PON ID
-	interfaceIndex := calculateInterfaceIndex(boardID, ponID)
-	onuTypeIndex := calculateONUTypeIndex(boardID, ponID)
+// listONUs reads the ONUs of a PON port, sorted by ID
+func (s *SNMPService) listONUs(snmp *gosnmp.GoSNMP, oltConfig *OltConfig, board, pon int) ([]SNMPONUInfo, error) {
+	// Walk the name column once: it defines the ONUs on the PON port
+	onus := make(map[int]*SNMPONUInfo)
+	nameOID := oltConfig.OnuIDNameOID
+	err := walkColumn(snmp, nameOID, func(pdu gosnmp.SnmpPDU) error {
+		if id, ok := columnIndex(nameOID, pdu.Name); ok {
+			onus[id] = &SNMPONUInfo{
+				Board: board,
+				PON:   pon,
+				ID:    id,
+				Name:  ExtractName(pdu.Value),
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SNMP walk failed: %w", snmpAuthError(err))
+	}
+
+	// Walk every other column once and join the values by ONU ID. Like
+	// missing values, columns that fail to walk are left empty.
+	if len(onus) > 0 {
+		for _, col := range onuColumns(oltConfig) {
+			if col.oid == "" {
+				continue
+			}
+			_ = walkColumn(snmp, col.oid, func(pdu gosnmp.SnmpPDU) error {
+				id, ok := columnIndex(col.oid, pdu.Name)
+				if onu := onus[id]; ok && onu != nil {
+					col.set(onu, pdu.Value)
+				}
+				return nil
+			})
+		}
+	}
 
-	config := &OltConfig{
-		BaseOID:                   baseOID1,
-		OnuIDNameOID:              ".500.10.2.3.3.1.2." + strconv.Itoa(interfaceIndex),
-		OnuTypeOID:                ".3.50.11.2.1.17." + strconv.Itoa(onuTypeIndex),
-		OnuSerialNumberOID:        ".500.10.2.3.3.1.18." + strconv.Itoa(interfaceIndex),
-		OnuRxPowerOID:             ".500.20.2.2.2.1.10." + strconv.Itoa(interfaceIndex),
-		OnuTxPowerOID:             ".3.50.12.1.1.14." + strconv.Itoa(onuTypeIndex),
-		OnuStatusOID:              ".500.10.2.3.8.1.4." + strconv.Itoa(interfaceIndex),
-		OnuIPAddressOID:           ".3.50.16.1.1.10." + strconv.Itoa(onuTypeIndex),
-		OnuDescriptionOID:         ".500.10.2.3.3.1.3." + strconv.Itoa(interfaceIndex),
-		OnuLastOnlineOID:          ".500.10.2.3.8.1.5." + strconv.Itoa(interfaceIndex),
-		OnuLastOfflineOID:         ".500.10.2.3.8.1.6." + strconv.Itoa(interfaceIndex),
-		OnuLastOfflineReasonOID:   ".500.10.2.3.8.1.7." + strconv.Itoa(interfaceIndex),
-		OnuGponOpticalDistanceOID: ".500.10.2.3.10.1.2." + strconv.Itoa(interfaceIndex),
+	now := time.Now()
+	onuInformationList := make([]SNMPONUInfo, 0, len(onus))
+	for _, onu := range onus {
+		if onu.LastOnline != "" {
+			onu.Uptime, _ = s.calculateUptime(onu.LastOnline, oltConfig.Location)
+		}
+		onu.setStateDuration(now, oltConfig.Location)
+		onuInformationList = append(onuInformationList, *onu)
 	}
 
-	return config, nil
+	// Sort ONU information by ID
+	sort.Slice(onuInformationList, func(i, j int) bool {
+		return onuInformationList[i].ID < onuInformationList[j].ID
+	})
+	return onuInformationList, nil
 }
 
-// calculateInterfaceIndex calculates interface index based on board and PON ID
-func calculateInterfaceIndex(boardID, ponID int) int {
-	// Based on the pattern from working configuration:
-	// Board 1 PON 1: 285278465
-	// Board 1 PON 2: 285278466
-	// Board 2 PON 1: 285278721
-	// Board 2 PON 4: 285278724
+// onuColumn is an ONU table column walked once per PON port
+type onuColumn struct {
+	oid string
+	set func(onu *SNMPONUInfo, value interface{})
+}
 
-	baseIndex := 285278464 // Base index for Board 1 PON 0
-	if boardID == 2 {
-		baseIndex = 285278720 // Base index for Board 2 PON 0
+// onuColumns returns the ONU table columns read besides the name
+func onuColumns(config *OltConfig) []onuColumn {
+	return []onuColumn{
+		{config.OnuTypeOID, func(onu *SNMPONUInfo, v interface{}) {
+			onu.OnuType = ExtractName(v)
+		}},
+		{config.OnuDescriptionOID, func(onu *SNMPONUInfo, v interface{}) {
+			onu.Description = ExtractName(v)
+		}},
+		{config.OnuSerialNumberOID, func(onu *SNMPONUInfo, v interface{}) {
+			onu.SerialNumber = ExtractSerialNumber(v)
+		}},
+		{config.OnuRxPowerOID, func(onu *SNMPONUInfo, v interface{}) {
+			if rx, err := ConvertAndMultiply(v); err == nil {
+				onu.RXPower = rx
+			}
+		}},
+		{config.OnuTxPowerOID, func(onu *SNMPONUInfo, v interface{}) {
+			if tx, err := ConvertAndMultiply(v); err == nil {
+				onu.TXPower = tx
+			}
+		}},
+		{config.OnuStatusOID, func(onu *SNMPONUInfo, v interface{}) {
+			onu.Status = ExtractAndGetStatus(v)
+		}},
+		{config.OnuIPAddressOID, func(onu *SNMPONUInfo, v interface{}) {
+			onu.IPAddress = ExtractName(v)
+		}},
+		{config.OnuLastOnlineOID, func(onu *SNMPONUInfo, v interface{}) {
+			onu.LastOnline = extractDateTime(v)
+		}},
+		{config.OnuLastOfflineOID, func(onu *SNMPONUInfo, v interface{}) {
+			onu.LastOffline = extractDateTime(v)
+		}},
+		{config.OnuLastOfflineReasonOID, func(onu *SNMPONUInfo, v interface{}) {
+			onu.LastOfflineReason = ExtractLastOfflineReason(v)
+		}},
+		{config.OnuGponOpticalDistanceOID, func(onu *SNMPONUInfo, v interface{}) {
+			onu.GponOpticalDistance = opticalDistance(v)
+		}},
 	}
+}
 
-	return baseIndex + ponID
+// extractDateTime returns a DateAndTime value as "2006-01-02 15:04:05", or ""
+// when the OLT reports none (all zero, e.g. an ONU that never went offline)
+func extractDateTime(v interface{}) string {
+	bytes, ok := v.([]byte)
+	if !ok {
+		return ""
+	}
+	datetime, err := ConvertByteArrayToDateTime(bytes)
+	if err != nil {
+		return ""
+	}
+	return datetime
 }
 
-// calculateONUTypeIndex calculates ONU type index based on board and PON ID
-func calculateONUTypeIndex(boardID, ponID int) int {
-	// Based on the pattern from working configuration:
-	// Board 1 PON 1: 268501248
-	// Board 1 PON 2: 268501504
-	// Board 2 PON 1: 268566784
-	// Board 2 PON 4: 268567552
+// opticalDistance returns an optical distance in meters as km ("1.2km")
+func opticalDistance(v interface{}) string {
+	if value, ok := v.(int); ok {
+		return fmt.Sprintf("%.1fkm", float64(value)/1000.0)
+	}
+	return ExtractGponOpticalDistance(v)
+}
+
+// setStateDuration sets how long the ONU has been online since it last came
+// online, or offline since it last went offline; it is left empty when that
+// time is unknown
+func (onu *SNMPONUInfo) setStateDuration(now time.Time, loc *time.Location) {
+	since := onu.LastOffline
+	if onu.Status == "Online" {
+		since = onu.LastOnline
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", since, loc)
+	if err != nil || t.After(now) {
+		return
+	}
+	d := now.Sub(t)
+	onu.StateDuration = ConvertDurationToString(d)
+	onu.StateDurationSeconds = int64(d.Seconds())
+}
+
+// columnIndex returns the first index of a row in a column walk, the ONU ID
+// in ONU tables (rx/tx power and IP address rows have a trailing ".1")
+func columnIndex(column, name string) (int, bool) {
+	suffix, ok := strings.CutPrefix(name, column+".")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.Atoi(strings.SplitN(suffix, ".", 2)[0])
+	return id, err == nil
+}
+
+// getOltConfig gets the column OIDs of the PON port of a request from its OID
+// profile, using the port ifIndex discovered on the OLT when available
+func (s *SNMPService) getOltConfig(snmp *gosnmp.GoSNMP, req SNMPRequest) (*OltConfig, error) {
+	driver, err := GetDriver(req.Vendor)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := SelectOIDProfile(req.Vendor, req.Model, req.Firmware, req.OIDProfile)
+	if err != nil {
+		return nil, err
+	}
 
-	baseIndex := 268501248 // Base index for Board 1 PON 1
-	if boardID == 2 {
-		baseIndex = 268566784 // Base index for Board 2 PON 1
+	loc, err := oltLocation(req.TimeZone)
+	if err != nil {
+		return nil, err
 	}
 
-	offset := (ponID - 1) * 256
-	return baseIndex + offset
+	port, err := resolvePONPort(snmp, PONPort{
+		Rack:  orOne(req.Rack),
+		Shelf: orOne(req.Shelf),
+		Slot:  req.BoardID,
+		Port:  req.PONID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	config, err := buildOltConfig(driver, profile, port)
+	if err != nil {
+		return nil, err
+	}
+	config.Location = loc
+	return config, nil
+}
+
+// oltLocation returns the time zone of an OLT clock, the server's when none
+// is given
+func oltLocation(timeZone string) (*time.Location, error) {
+	if timeZone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid time zone %q", ErrSNMPConfig, timeZone)
+	}
+	return loc, nil
+}
+
+// buildOltConfig fills the OID profile with the indexes of a PON port
+func buildOltConfig(driver Driver, profile OIDProfile, port PONPort) (*OltConfig, error) {
+	indexes, err := driver.OIDIndexes(port)
+	if err != nil {
+		return nil, err
+	}
+	config, err := profile.Build(indexes)
+	if err != nil {
+		return nil, err
+	}
+	config.Port = port
+	config.Location = time.Local
+	return config, nil
 }
 
 // SNMP getter methods
 func (s *SNMPService) getONUName(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
-	oid := config.BaseOID + config.OnuIDNameOID + "." + onuID
+	oid := config.OnuIDNameOID + "." + onuID
 	result, err := snmp.Get([]string{oid})
 	if err != nil {
 		return "", err
@@ -344,7 +501,7 @@ func (s *SNMPService) getONUName(snmp *gosnmp.GoSNMP, config *OltConfig, onuID s
 }
 
 func (s *SNMPService) getONUType(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
-	oid := ".1.3.6.1.4.1.3902.1012" + config.OnuTypeOID + "." + onuID
+	oid := config.OnuTypeOID + "." + onuID
 	result, err := snmp.Get([]string{oid})
 	if err != nil {
 		return "", err
@@ -356,7 +513,7 @@ func (s *SNMPService) getONUType(snmp *gosnmp.GoSNMP, config *OltConfig, onuID s
 }
 
 func (s *SNMPService) getONUDescription(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
-	oid := config.BaseOID + config.OnuDescriptionOID + "." + onuID
+	oid := config.OnuDescriptionOID + "." + onuID
 	result, err := snmp.Get([]string{oid})
 	if err != nil {
 		return "", err
@@ -368,7 +525,7 @@ func (s *SNMPService) getONUDescription(snmp *gosnmp.GoSNMP, config *OltConfig,
 }
 
 func (s *SNMPService) getSerialNumber(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
-	oid := config.BaseOID + config.OnuSerialNumberOID + "." + onuID
+	oid := config.OnuSerialNumberOID + "." + onuID
 	result, err := snmp.Get([]string{oid})
 	if err != nil {
 		return "", err
@@ -380,7 +537,7 @@ func (s *SNMPService) getSerialNumber(snmp *gosnmp.GoSNMP, config *OltConfig, on
 }
 
 func (s *SNMPService) getRxPower(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
-	oid := config.BaseOID + config.OnuRxPowerOID + "." + onuID + ".1"
+	oid := config.OnuRxPowerOID + "." + onuID + ".1"
 	result, err := snmp.Get([]string{oid})
 	if err != nil {
 		return "", err
@@ -393,7 +550,7 @@ func (s *SNMPService) getRxPower(snmp *gosnmp.GoSNMP, config *OltConfig, onuID s
 }
 
 func (s *SNMPService) getTxPower(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
-	oid := ".1.3.6.1.4.1.3902.1012" + config.OnuTxPowerOID + "." + onuID + ".1"
+	oid := config.OnuTxPowerOID + "." + onuID + ".1"
 	result, err := snmp.Get([]string{oid})
 	if err != nil {
 		return "", err
@@ -406,7 +563,7 @@ func (s *SNMPService) getTxPower(snmp *gosnmp.GoSNMP, config *OltConfig, onuID s
 }
 
 func (s *SNMPService) getStatus(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
-	oid := config.BaseOID + config.OnuStatusOID + "." + onuID
+	oid := config.OnuStatusOID + "." + onuID
 	result, err := snmp.Get([]string{oid})
 	if err != nil {
 		return "", err
@@ -418,7 +575,7 @@ func (s *SNMPService) getStatus(snmp *gosnmp.GoSNMP, config *OltConfig, onuID st
 }
 
 func (s *SNMPService) getIPAddress(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
-	oid := ".1.3.6.1.4.1.3902.1012" + config.OnuIPAddressOID + "." + onuID + ".1"
+	oid := config.OnuIPAddressOID + "." + onuID + ".1"
 	result, err := snmp.Get([]string{oid})
 	if err != nil {
 		return "", err
@@ -430,7 +587,7 @@ func (s *SNMPService) getIPAddress(snmp *gosnmp.GoSNMP, config *OltConfig, onuID
 }
 
 func (s *SNMPService) getLastOnline(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
-	oid := config.BaseOID + config.OnuLastOnlineOID + "." + onuID
+	oid := config.OnuLastOnlineOID + "." + onuID
 	result, err := snmp.Get([]string{oid})
 	if err != nil {
 		return "", err
@@ -443,13 +600,41 @@ func (s *SNMPService) getLastOnline(snmp *gosnmp.GoSNMP, config *OltConfig, onuI
 	return "", fmt.Errorf("no response")
 }
 
-func (s *SNMPService) calculateUptime(lastOnline string) (string, error) {
+func (s *SNMPService) getLastOffline(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
+	oid := config.OnuLastOfflineOID + "." + onuID
+	result, err := snmp.Get([]string{oid})
+	if err != nil {
+		return "", err
+	}
+	if len(result.Variables) > 0 {
+		if bytes, ok := result.Variables[0].Value.([]byte); ok {
+			return ConvertByteArrayToDateTime(bytes)
+		}
+	}
+	return "", fmt.Errorf("no response")
+}
+
+func (s *SNMPService) getLastOfflineReason(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
+	oid := config.OnuLastOfflineReasonOID + "." + onuID
+	result, err := snmp.Get([]string{oid})
+	if err != nil {
+		return "", err
+	}
+	if len(result.Variables) > 0 {
+		return ExtractLastOfflineReason(result.Variables[0].Value), nil
+	}
+	return "", fmt.Errorf("no response")
+}
+
+// calculateUptime returns the time since the ONU came online, read from the
+// OLT clock in loc
+func (s *SNMPService) calculateUptime(lastOnline string, loc *time.Location) (string, error) {
 	currentTime := time.Now()
 
 	// Try multiple date formats
 	formats := []string{
 		"2006-01-02 15:04:05",
-		"2006-01-02T15:04:05Z",
+		time.RFC3339,
 		"2006-01-02 15:04:05.000",
 	}
 
@@ -457,7 +642,7 @@ func (s *SNMPService) calculateUptime(lastOnline string) (string, error) {
 	var err error
 
 	for _, format := range formats {
-		lastOnlineTime, err = time.Parse(format, lastOnline)
+		lastOnlineTime, err = time.ParseInLocation(format, lastOnline, loc)
 		if err == nil {
 			break
 		}
@@ -477,18 +662,13 @@ func (s *SNMPService) calculateUptime(lastOnline string) (string, error) {
 }
 
 func (s *SNMPService) getOpticalDistance(snmp *gosnmp.GoSNMP, config *OltConfig, onuID string) (string, error) {
-	oid := config.BaseOID + config.OnuGponOpticalDistanceOID + "." + onuID
+	oid := config.OnuGponOpticalDistanceOID + "." + onuID
 	result, err := snmp.Get([]string{oid})
 	if err != nil {
 		return "", err
 	}
 	if len(result.Variables) > 0 {
-		// Convert to km format
-		if value, ok := result.Variables[0].Value.(int); ok {
-			distance := float64(value) / 1000.0 // Convert to km
-			return fmt.Sprintf("%.1fkm", distance), nil
-		}
-		return ExtractGponOpticalDistance(result.Variables[0].Value), nil
+		return opticalDistance(result.Variables[0].Value), nil
 	}
 	return "", fmt.Errorf("no response")
 }
@@ -0,0 +1,281 @@
+package olt
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// testAgent is a minimal SNMPv2c agent answering Get, GetNext and GetBulk
+// requests from a fixed table; it counts the requests it answers
+type testAgent struct {
+	conn     net.PacketConn
+	oids     []string // sorted in OID order
+	values   map[string]gosnmp.SnmpPDU
+	requests atomic.Int64
+}
+
+// newTestAgent serves pdus on a local UDP port until the test ends
+func newTestAgent(tb testing.TB, pdus []gosnmp.SnmpPDU) *testAgent {
+	tb.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	a := &testAgent{conn: conn, values: make(map[string]gosnmp.SnmpPDU)}
+	for _, pdu := range pdus {
+		a.oids = append(a.oids, pdu.Name)
+		a.values[pdu.Name] = pdu
+	}
+	sort.Slice(a.oids, func(i, j int) bool { return compareOID(a.oids[i], a.oids[j]) < 0 })
+
+	go a.serve()
+	tb.Cleanup(func() { conn.Close() })
+	return a
+}
+
+// client returns a connected SNMPv2c client of the agent
+func (a *testAgent) client(tb testing.TB) *gosnmp.GoSNMP {
+	tb.Helper()
+	snmp := &gosnmp.GoSNMP{
+		Target:    "127.0.0.1",
+		Port:      uint16(a.conn.LocalAddr().(*net.UDPAddr).Port),
+		Community: "public",
+		Version:   gosnmp.Version2c,
+		Timeout:   2 * time.Second,
+	}
+	if err := snmp.Connect(); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { snmp.Conn.Close() })
+	return snmp
+}
+
+func (a *testAgent) serve() {
+	decoder := &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "public"}
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := a.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		req, err := decoder.SnmpDecodePacket(buf[:n])
+		if err != nil {
+			continue
+		}
+		a.requests.Add(1)
+
+		resp := &gosnmp.SnmpPacket{
+			Version:   req.Version,
+			Community: req.Community,
+			PDUType:   gosnmp.GetResponse,
+			RequestID: req.RequestID,
+			Variables: a.answer(req),
+		}
+		out, err := resp.MarshalMsg()
+		if err != nil {
+			continue
+		}
+		a.conn.WriteTo(out, addr)
+	}
+}
+
+// answer returns the variables of the response to a request
+func (a *testAgent) answer(req *gosnmp.SnmpPacket) []gosnmp.SnmpPDU {
+	var vars []gosnmp.SnmpPDU
+	switch req.PDUType {
+	case gosnmp.GetRequest:
+		for _, v := range req.Variables {
+			pdu, ok := a.values[v.Name]
+			if !ok {
+				pdu = gosnmp.SnmpPDU{Name: v.Name, Type: gosnmp.NoSuchInstance}
+			}
+			vars = append(vars, pdu)
+		}
+	case gosnmp.GetNextRequest:
+		for _, v := range req.Variables {
+			vars = append(vars, a.next(v.Name))
+		}
+	case gosnmp.GetBulkRequest:
+		names := make([]string, len(req.Variables))
+		for i, v := range req.Variables {
+			names[i] = v.Name
+		}
+		for r := uint32(0); r < req.MaxRepetitions; r++ {
+			for i, name := range names {
+				pdu := a.next(name)
+				vars = append(vars, pdu)
+				names[i] = pdu.Name
+			}
+		}
+	}
+	return vars
+}
+
+// next returns the variable following an OID, or endOfMibView
+func (a *testAgent) next(oid string) gosnmp.SnmpPDU {
+	i := sort.Search(len(a.oids), func(i int) bool { return compareOID(a.oids[i], oid) > 0 })
+	if i == len(a.oids) {
+		return gosnmp.SnmpPDU{Name: oid, Type: gosnmp.EndOfMibView}
+	}
+	return a.values[a.oids[i]]
+}
+
+// compareOID compares two dotted OIDs numerically
+func compareOID(a, b string) int {
+	pa := strings.Split(strings.TrimPrefix(a, "."), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "."), ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		x, _ := strconv.Atoi(pa[i])
+		y, _ := strconv.Atoi(pb[i])
+		if x != y {
+			return x - y
+		}
+	}
+	return len(pa) - len(pb)
+}
+
+// onuTable returns the ONU table of a ZTE PON port with n ONUs
+func onuTable(tb testing.TB, n int) (*OltConfig, []gosnmp.SnmpPDU) {
+	tb.Helper()
+	driver, err := GetDriver("zte")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	profile, err := SelectOIDProfile("zte", "", "", "")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	config, err := buildOltConfig(driver, profile, PONPort{Rack: 1, Shelf: 1, Slot: 2, Port: 1})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	octets := func(oid, s string) gosnmp.SnmpPDU {
+		return gosnmp.SnmpPDU{Name: oid, Type: gosnmp.OctetString, Value: []byte(s)}
+	}
+	integer := func(oid string, v int) gosnmp.SnmpPDU {
+		return gosnmp.SnmpPDU{Name: oid, Type: gosnmp.Integer, Value: v}
+	}
+	var pdus []gosnmp.SnmpPDU
+	for id := 1; id <= n; id++ {
+		row := "." + strconv.Itoa(id)
+		pdus = append(pdus,
+			octets(config.OnuIDNameOID+row, "onu-"+strconv.Itoa(id)),
+			octets(config.OnuTypeOID+row, "F660V8.0"),
+			octets(config.OnuDescriptionOID+row, "customer "+strconv.Itoa(id)),
+			octets(config.OnuSerialNumberOID+row, "ZTEGC0000001"),
+			integer(config.OnuRxPowerOID+row+".1", -20123),
+			integer(config.OnuTxPowerOID+row+".1", 2200),
+			integer(config.OnuStatusOID+row, 4),
+			octets(config.OnuIPAddressOID+row+".1", "10.0.0.1"),
+			integer(config.OnuGponOpticalDistanceOID+row, 1520),
+		)
+	}
+	return config, pdus
+}
+
+func TestListONUs(t *testing.T) {
+	config, pdus := onuTable(t, 20)
+	agent := newTestAgent(t, pdus)
+	snmp := agent.client(t)
+
+	s := NewFinalSNMPService(2 * time.Second)
+	onus, err := s.listONUs(snmp, config, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(onus) != 20 {
+		t.Fatalf("listONUs returned %d ONUs, want 20", len(onus))
+	}
+	for i, onu := range onus {
+		if onu.ID != i+1 || onu.Name != "onu-"+strconv.Itoa(i+1) || onu.OnuType != "F660V8.0" || onu.SerialNumber == "" {
+			t.Errorf("ONU %d = %+v", i+1, onu)
+		}
+	}
+}
+
+func TestSetStateDuration(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	now := time.Date(2026, 10, 18, 4, 0, 0, 0, time.UTC) // 11:00 WIB
+
+	tests := []struct {
+		onu     SNMPONUInfo
+		loc     *time.Location
+		want    string
+		seconds int64
+	}{
+		{SNMPONUInfo{Status: "Online", LastOnline: "2026-10-18 10:00:00"}, wib, "0 days 1 hours 0 minutes 0 seconds", 3600},
+		{SNMPONUInfo{Status: "Online", LastOnline: "2026-10-18 03:30:00"}, time.UTC, "0 days 0 hours 30 minutes 0 seconds", 1800},
+		{SNMPONUInfo{Status: "LOS", LastOnline: "2026-10-18 10:00:00", LastOffline: "2026-10-17 11:00:00"}, wib, "1 days 0 hours 0 minutes 0 seconds", 86400},
+		// an OLT clock ahead of the server, read in the wrong zone
+		{SNMPONUInfo{Status: "Online", LastOnline: "2026-10-18 10:00:00"}, time.UTC, "", 0},
+		{SNMPONUInfo{Status: "Offline", LastOffline: "0000-00-00 00:00:00"}, wib, "", 0},
+	}
+
+	for _, tt := range tests {
+		onu := tt.onu
+		onu.setStateDuration(now, tt.loc)
+		if onu.StateDuration != tt.want || onu.StateDurationSeconds != tt.seconds {
+			t.Errorf("setStateDuration(%+v, %s) = %q, %d; want %q, %d",
+				tt.onu, tt.loc, onu.StateDuration, onu.StateDurationSeconds, tt.want, tt.seconds)
+		}
+	}
+}
+
+// BenchmarkListONUs compares the round trips of reading a PON port with 64
+// ONUs by walking each column with GETBULK against one GET per ONU and column
+func BenchmarkListONUs(b *testing.B) {
+	config, pdus := onuTable(b, 64)
+	s := NewFinalSNMPService(2 * time.Second)
+
+	b.Run("bulk", func(b *testing.B) {
+		agent := newTestAgent(b, pdus)
+		snmp := agent.client(b)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := s.listONUs(snmp, config, 2, 1); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(agent.requests.Load())/float64(b.N), "round-trips/op")
+	})
+
+	b.Run("get-per-onu", func(b *testing.B) {
+		agent := newTestAgent(b, pdus)
+		snmp := agent.client(b)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			var ids []string
+			err := snmp.Walk(config.OnuIDNameOID, func(pdu gosnmp.SnmpPDU) error {
+				if id, ok := columnIndex(config.OnuIDNameOID, pdu.Name); ok {
+					ids = append(ids, strconv.Itoa(id))
+				}
+				return nil
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, id := range ids {
+				s.getONUType(snmp, config, id)
+				s.getONUDescription(snmp, config, id)
+				s.getSerialNumber(snmp, config, id)
+				s.getRxPower(snmp, config, id)
+				s.getTxPower(snmp, config, id)
+				s.getStatus(snmp, config, id)
+				s.getIPAddress(snmp, config, id)
+				s.getLastOnline(snmp, config, id)
+				s.getLastOffline(snmp, config, id)
+				s.getLastOfflineReason(snmp, config, id)
+				s.getOpticalDistance(snmp, config, id)
+			}
+		}
+		b.ReportMetric(float64(agent.requests.Load())/float64(b.N), "round-trips/op")
+	})
+}
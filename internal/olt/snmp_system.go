@@ -0,0 +1,329 @@
+package olt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// SNMPv2-MIB system group
+const (
+	sysDescrOID  = ".1.3.6.1.2.1.1.1.0"
+	sysUpTimeOID = ".1.3.6.1.2.1.1.3.0"
+	sysNameOID   = ".1.3.6.1.2.1.1.5.0"
+)
+
+// Card load and temperature limits; above them a card is warning or critical
+const (
+	cardUsageWarning        = 80.0 // %
+	cardUsageCritical       = 95.0 // %
+	cardTemperatureWarning  = 70.0 // °C
+	cardTemperatureCritical = 85.0 // °C
+)
+
+// unitStatusHealth rates the status names of cards, fans and power supplies.
+// Other names the OID profile defines are critical; absent units do not
+// count towards the OLT health.
+var unitStatusHealth = map[string]string{
+	"inService":   "normal",
+	"normal":      "normal",
+	"hwOnline":    "warning",
+	"configuring": "warning",
+	"notPresent":  "absent",
+}
+
+// healthRank orders the health values counting towards the OLT health
+var healthRank = map[string]int{"normal": 1, "warning": 2, "critical": 3}
+
+// CardHealth represents a card of an OLT; readings the OLT does not report are nil
+type CardHealth struct {
+	Rack            int
+	Shelf           int
+	Slot            int
+	Type            string
+	Status          string
+	SoftwareVersion string
+	CPUUsage        *float64 // %
+	MemoryUsage     *float64 // %
+	Temperature     *float64 // °C
+	Health          string
+}
+
+// UnitHealth represents a fan or power supply of an OLT
+type UnitHealth struct {
+	Index  string // rack/shelf/unit
+	Status string
+	Health string
+}
+
+// SystemHealth represents the identity, cards, fans and power supplies of an
+// OLT. Health is the worst health of its units; Problems lists the units and
+// readings that are not normal.
+type SystemHealth struct {
+	Host          string
+	Name          string
+	Description   string
+	ObjectID      string
+	Uptime        time.Duration
+	OIDProfile    string
+	Cards         []CardHealth
+	Fans          []UnitHealth
+	PowerSupplies []UnitHealth
+	Health        string
+	Problems      []string
+	ExecutionTime string
+	Timestamp     time.Time
+}
+
+// GetSystemHealth retrieves the system group, card table, fans and power
+// supplies of an OLT and rates their health
+func (s *SNMPService) GetSystemHealth(ctx context.Context, req SNMPRequest) (*SystemHealth, error) {
+	startTime := time.Now()
+
+	profile, err := SelectOIDProfile(req.Vendor, req.Model, req.Firmware, req.OIDProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	snmp, err := s.setupSNMPConnection(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup SNMP connection: %w", err)
+	}
+	defer snmp.Conn.Close()
+
+	result := &SystemHealth{Host: req.Host, OIDProfile: profile.Name}
+
+	response, err := snmp.Get([]string{sysNameOID, sysDescrOID, sysObjectIDOID, sysUpTimeOID})
+	if err != nil {
+		return nil, fmt.Errorf("SNMP get failed: %w", snmpAuthError(err))
+	}
+	for _, pdu := range response.Variables {
+		switch "." + strings.TrimPrefix(pdu.Name, ".") {
+		case sysNameOID:
+			result.Name = ExtractName(pdu.Value)
+		case sysDescrOID:
+			result.Description = ExtractName(pdu.Value)
+		case sysObjectIDOID:
+			result.ObjectID, _ = pdu.Value.(string)
+		case sysUpTimeOID:
+			if ticks, ok := pdu.Value.(uint32); ok {
+				result.Uptime = time.Duration(ticks) * 10 * time.Millisecond
+			}
+		}
+	}
+
+	cards, err := readCards(snmp, profile)
+	if err != nil {
+		return nil, err
+	}
+	if result.Fans, err = readUnits(snmp, profile, "fan_status"); err != nil {
+		return nil, err
+	}
+	if result.PowerSupplies, err = readUnits(snmp, profile, "power_status"); err != nil {
+		return nil, err
+	}
+	result.Cards = cards
+
+	result.rate(profile)
+	result.ExecutionTime = fmt.Sprintf("%.2fs", time.Since(startTime).Seconds())
+	result.Timestamp = time.Now()
+	return result, nil
+}
+
+// readCards walks the card table, indexed by rack.shelf.slot
+func readCards(snmp *gosnmp.GoSNMP, profile OIDProfile) ([]CardHealth, error) {
+	cards := make(map[string]*CardHealth)
+	card := func(row string) *CardHealth {
+		if c, ok := cards[row]; ok {
+			return c
+		}
+		nums := rowNumbers(row, 3)
+		c := &CardHealth{Rack: nums[0], Shelf: nums[1], Slot: nums[2]}
+		cards[row] = c
+		return c
+	}
+
+	columns := []struct {
+		name string
+		set  func(c *CardHealth, pdu gosnmp.SnmpPDU)
+	}{
+		{"card_type", func(c *CardHealth, pdu gosnmp.SnmpPDU) {
+			c.Type = ExtractName(pdu.Value)
+		}},
+		{"card_status", func(c *CardHealth, pdu gosnmp.SnmpPDU) {
+			c.Status = profile.enumName("card_status", rawValue(pdu))
+		}},
+		{"card_software_version", func(c *CardHealth, pdu gosnmp.SnmpPDU) {
+			c.SoftwareVersion = ExtractName(pdu.Value)
+		}},
+		{"card_cpu_usage", func(c *CardHealth, pdu gosnmp.SnmpPDU) {
+			c.CPUUsage = numericReading(pdu, profile.Scales["card_cpu_usage"])
+		}},
+		{"card_memory_usage", func(c *CardHealth, pdu gosnmp.SnmpPDU) {
+			c.MemoryUsage = numericReading(pdu, profile.Scales["card_memory_usage"])
+		}},
+		{"card_temperature", func(c *CardHealth, pdu gosnmp.SnmpPDU) {
+			c.Temperature = numericReading(pdu, profile.Scales["card_temperature"])
+		}},
+	}
+	for _, col := range columns {
+		err := walkTable(snmp, profile, col.name, func(row string, pdu gosnmp.SnmpPDU) {
+			col.set(card(row), pdu)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	list := make([]CardHealth, 0, len(cards))
+	for _, c := range cards {
+		list = append(list, *c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Rack != b.Rack {
+			return a.Rack < b.Rack
+		}
+		if a.Shelf != b.Shelf {
+			return a.Shelf < b.Shelf
+		}
+		return a.Slot < b.Slot
+	})
+	return list, nil
+}
+
+// readUnits walks the status column of the fans or power supplies
+func readUnits(snmp *gosnmp.GoSNMP, profile OIDProfile, column string) ([]UnitHealth, error) {
+	var units []UnitHealth
+	err := walkTable(snmp, profile, column, func(row string, pdu gosnmp.SnmpPDU) {
+		status := profile.enumName(column, rawValue(pdu))
+		units = append(units, UnitHealth{
+			Index:  strings.ReplaceAll(row, ".", "/"),
+			Status: status,
+			Health: statusHealth(profile, column, status),
+		})
+	})
+	return units, err
+}
+
+// walkTable walks an OLT-wide column of the OID profile, calling fn with the
+// row index of each value; columns the profile does not define are skipped
+func walkTable(snmp *gosnmp.GoSNMP, profile OIDProfile, column string, fn func(row string, pdu gosnmp.SnmpPDU)) error {
+	oid := profile.Columns[column]
+	if oid == "" {
+		return nil
+	}
+	err := walkColumn(snmp, oid, func(pdu gosnmp.SnmpPDU) error {
+		if row, ok := strings.CutPrefix(pdu.Name, oid+"."); ok {
+			fn(row, pdu)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("SNMP walk of %s failed: %w", column, snmpAuthError(err))
+	}
+	return nil
+}
+
+// rate sets the health of the cards and of the OLT, and lists the problems
+func (h *SystemHealth) rate(profile OIDProfile) {
+	worst := 0
+	note := func(health, format string, args ...any) {
+		if health != "normal" && health != "absent" {
+			h.Problems = append(h.Problems, fmt.Sprintf(format, args...))
+		}
+		worst = max(worst, healthRank[health])
+	}
+
+	for i := range h.Cards {
+		c := &h.Cards[i]
+		name := fmt.Sprintf("card %d/%d/%d", c.Rack, c.Shelf, c.Slot)
+		if c.Type != "" {
+			name += " (" + c.Type + ")"
+		}
+
+		c.Health = statusHealth(profile, "card_status", c.Status)
+		if c.Status != "" {
+			note(c.Health, "%s: %s", name, c.Status)
+		}
+		for _, r := range []struct {
+			label             string
+			value             *float64
+			warning, critical float64
+			unit              string
+		}{
+			{"cpu", c.CPUUsage, cardUsageWarning, cardUsageCritical, "%"},
+			{"memory", c.MemoryUsage, cardUsageWarning, cardUsageCritical, "%"},
+			{"temperature", c.Temperature, cardTemperatureWarning, cardTemperatureCritical, "°C"},
+		} {
+			health := "normal"
+			switch {
+			case r.value == nil:
+				continue
+			case *r.value > r.critical:
+				health = "critical"
+			case *r.value > r.warning:
+				health = "warning"
+			}
+			note(health, "%s: %s %g%s", name, r.label, *r.value, r.unit)
+			if healthRank[health] > healthRank[c.Health] {
+				c.Health = health
+			}
+		}
+	}
+	for _, fan := range h.Fans {
+		note(fan.Health, "fan %s: %s", fan.Index, fan.Status)
+	}
+	for _, power := range h.PowerSupplies {
+		note(power.Health, "power supply %s: %s", power.Index, power.Status)
+	}
+
+	h.Health = "unknown"
+	for health, rank := range healthRank {
+		if rank == worst {
+			h.Health = health
+		}
+	}
+}
+
+// statusHealth rates a unit status name. Names the OID profile defines but
+// unitStatusHealth does not know are critical; raw values without a name
+// are unknown.
+func statusHealth(profile OIDProfile, column, status string) string {
+	if health, ok := unitStatusHealth[status]; ok {
+		return health
+	}
+	for _, name := range profile.Enums[column] {
+		if name == status {
+			return "critical"
+		}
+	}
+	return "unknown"
+}
+
+// rawValue returns the value of a status column as text
+func rawValue(pdu gosnmp.SnmpPDU) string {
+	if pdu.Type == gosnmp.OctetString {
+		return ExtractName(pdu.Value)
+	}
+	return gosnmp.ToBigInt(pdu.Value).String()
+}
+
+// rowNumbers parses a row index such as "1.1.3" into n numbers, padding
+// shorter indexes with leading 1s (a slot-only index is rack 1, shelf 1)
+func rowNumbers(row string, n int) []int {
+	parts := strings.Split(row, ".")
+	nums := make([]int, n)
+	for i := range nums {
+		nums[i] = 1
+	}
+	for i, j := len(parts)-1, n-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		nums[j], _ = strconv.Atoi(parts[i])
+	}
+	return nums
+}
@@ -0,0 +1,374 @@
+package olt
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// MaxTrafficSampleInterval caps the wait between the two counter reads of a
+// request asking for a fresh rate
+const MaxTrafficSampleInterval = time.Minute
+
+// trafficSampleMinInterval is the shortest time between two samples a rate
+// is computed over; a poll closer to the stored sample than this (e.g. by
+// another client) gets no rate and leaves that sample in place
+const trafficSampleMinInterval = time.Second
+
+// trafficSampleMaxAge is how long a counter sample is kept as the previous
+// sample of the next poll; older samples would average the rate over too long
+const trafficSampleMaxAge = 30 * time.Minute
+
+// ponCapacities lists the PON line rates in bit/s by ifDescr name prefix;
+// other names are GPON (2.488/1.244 Gbit/s)
+var ponCapacities = []struct {
+	prefix     string
+	down, up   float64
+	technology string
+}{
+	{"xgs", 9.953e9, 9.953e9, "XGS-PON"},
+	{"xg", 9.953e9, 2.488e9, "XG-PON"},
+	{"epon", 1e9, 1e9, "EPON"},
+}
+
+// GemportTraffic represents the traffic of one gemport of an ONU
+type GemportTraffic struct {
+	Gemport          int
+	UpstreamOctets   uint64
+	DownstreamOctets uint64
+	UpstreamBps      float64
+	DownstreamBps    float64
+}
+
+// ONUTraffic represents the traffic counters of an ONU and the bit rates
+// computed from the previous sample. RateAvailable is false until a previous
+// sample exists or when a counter was reset (e.g. the ONU rebooted); a
+// Counter32 reset is told from a wrap by the line rate of the PON port.
+type ONUTraffic struct {
+	Board            int
+	PON              int
+	ID               int
+	Name             string
+	UpstreamOctets   uint64
+	DownstreamOctets uint64
+	UpstreamBps      float64
+	DownstreamBps    float64
+	RateAvailable    bool
+	Interval         time.Duration // time between the samples
+	Gemports         []GemportTraffic
+}
+
+// PONTraffic represents the traffic of the ONUs on a PON port
+type PONTraffic struct {
+	Host                  string
+	BoardID               int
+	PONID                 int
+	Interface             string
+	Technology            string
+	TotalONUs             int
+	UpstreamBps           float64
+	DownstreamBps         float64
+	UpstreamCapacityBps   float64
+	DownstreamCapacityBps float64
+	ONUs                  []ONUTraffic
+	ExecutionTime         string
+	Timestamp             time.Time
+}
+
+// counterSample is a counter value read at a point in time
+type counterSample struct {
+	value uint64
+	is64  bool
+	at    time.Time
+}
+
+// counterStore keeps the last sample of every counter row per OLT
+type counterStore struct {
+	mu      sync.Mutex
+	samples map[string]counterSample // by OLT and row OID
+}
+
+var trafficCounters = &counterStore{samples: make(map[string]counterSample)}
+
+// swap stores a sample and returns the previous one, if still recent. A
+// sample taken less than trafficSampleMinInterval after the stored one is
+// dropped and no previous sample is returned.
+func (c *counterStore) swap(key string, sample counterSample) (counterSample, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev, ok := c.samples[key]
+	if ok && sample.at.Sub(prev.at) < trafficSampleMinInterval {
+		return counterSample{}, false
+	}
+	c.samples[key] = sample
+	if !ok || sample.at.Sub(prev.at) > trafficSampleMaxAge {
+		return counterSample{}, false
+	}
+	return prev, true
+}
+
+// prune drops the samples no poll can use any more
+func (c *counterStore) prune() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, sample := range c.samples {
+		if time.Since(sample.at) > trafficSampleMaxAge {
+			delete(c.samples, key)
+		}
+	}
+}
+
+// counterDelta returns the increase of a counter between two samples. A
+// Counter32 lower than before wrapped once, unless the wrap would mean more
+// than maxBps (the line rate of the port) since the previous sample: then it
+// was reset, as is a lower Counter64, which cannot wrap in a poll interval,
+// and the delta is unknown.
+func counterDelta(prev, cur counterSample, maxBps float64) (uint64, bool) {
+	switch {
+	case cur.value >= prev.value:
+		return cur.value - prev.value, true
+	case !cur.is64 && !prev.is64 && prev.value <= math.MaxUint32:
+		delta := cur.value + (math.MaxUint32 + 1) - prev.value
+		if float64(delta)*8 > maxBps*cur.at.Sub(prev.at).Seconds() {
+			return 0, false
+		}
+		return delta, true
+	}
+	return 0, false
+}
+
+// counterRate returns the bit rate between two samples of a counter on a
+// port with a line rate of maxBps
+func counterRate(prev, cur counterSample, maxBps float64) (float64, bool) {
+	delta, ok := counterDelta(prev, cur, maxBps)
+	seconds := cur.at.Sub(prev.at).Seconds()
+	if !ok || seconds <= 0 {
+		return 0, false
+	}
+	return float64(delta) * 8 / seconds, true
+}
+
+// ponCapacity returns the line rates and technology of a PON port from its
+// ifDescr name; other and unnamed ports are taken as GPON
+func ponCapacity(name string) (down, up float64, technology string) {
+	name = strings.ToLower(name)
+	for _, c := range ponCapacities {
+		if strings.HasPrefix(name, c.prefix) {
+			return c.down, c.up, c.technology
+		}
+	}
+	return 2.488e9, 1.244e9, "GPON"
+}
+
+// GetPONTraffic retrieves the traffic counters of the ONUs on a PON port and
+// their bit rates. Rates are computed against the previous poll of the same
+// counters; with interval > 0 the counters are read twice, interval apart.
+func (s *SNMPService) GetPONTraffic(ctx context.Context, req SNMPRequest, interval time.Duration) (*PONTraffic, error) {
+	startTime := time.Now()
+
+	snmp, err := s.setupSNMPConnection(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup SNMP connection: %w", err)
+	}
+	defer snmp.Conn.Close()
+
+	oltConfig, err := s.getOltConfig(snmp, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OLT config: %w", err)
+	}
+	if oltConfig.OnuUpstreamOctetsOID == "" && oltConfig.OnuDownstreamOctetsOID == "" {
+		return nil, fmt.Errorf("%w: OID profile %s has no traffic counter columns", ErrSNMPConfig, oltConfig.Profile)
+	}
+
+	names := make(map[int]string)
+	err = walkColumn(snmp, oltConfig.OnuIDNameOID, func(pdu gosnmp.SnmpPDU) error {
+		if id, ok := columnIndex(oltConfig.OnuIDNameOID, pdu.Name); ok {
+			names[id] = ExtractName(pdu.Value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SNMP walk failed: %w", snmpAuthError(err))
+	}
+
+	trafficCounters.prune()
+	if interval > 0 {
+		if _, err := readTraffic(snmp, oltConfig); err != nil {
+			return nil, err
+		}
+		select {
+		case <-time.After(min(interval, MaxTrafficSampleInterval)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	rows, err := readTraffic(snmp, oltConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PONTraffic{
+		Host:      req.Host,
+		BoardID:   req.BoardID,
+		PONID:     req.PONID,
+		Interface: oltConfig.Port.Name,
+		ONUs:      make([]ONUTraffic, 0, len(names)),
+	}
+	result.DownstreamCapacityBps, result.UpstreamCapacityBps, result.Technology = ponCapacity(oltConfig.Port.Name)
+
+	for id, name := range names {
+		onu := ONUTraffic{Board: req.BoardID, PON: req.PONID, ID: id, Name: name}
+		onu.add(rows[id])
+		result.ONUs = append(result.ONUs, onu)
+		result.UpstreamBps += onu.UpstreamBps
+		result.DownstreamBps += onu.DownstreamBps
+	}
+	sort.Slice(result.ONUs, func(i, j int) bool { return result.ONUs[i].ID < result.ONUs[j].ID })
+
+	result.TotalONUs = len(result.ONUs)
+	result.ExecutionTime = fmt.Sprintf("%.2fs", time.Since(startTime).Seconds())
+	result.Timestamp = time.Now()
+	return result, nil
+}
+
+// GetONUTraffic retrieves the traffic counters and bit rates of one ONU. The
+// counters of the whole PON port are read so that the samples kept for later
+// polls stay consistent.
+func (s *SNMPService) GetONUTraffic(ctx context.Context, req SNMPRequest, onuID int, interval time.Duration) (*ONUTraffic, error) {
+	pon, err := s.GetPONTraffic(ctx, req, interval)
+	if err != nil {
+		return nil, err
+	}
+	for _, onu := range pon.ONUs {
+		if onu.ID == onuID {
+			return &onu, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d on %d/%d", ErrONUNotFound, onuID, req.BoardID, req.PONID)
+}
+
+// trafficRow is the traffic of a counter row: an ONU, or one of its gemports
+// when the OLT counts per gemport
+type trafficRow struct {
+	gemport  int // 0 for ONU rows
+	up, down uint64
+	upBps    float64
+	downBps  float64
+	known    bool          // both rates could be computed
+	interval time.Duration // time since the previous sample
+}
+
+// readTraffic walks the counter columns, stores the samples and returns the
+// rows of every ONU with the rates since the stored samples
+func readTraffic(snmp *gosnmp.GoSNMP, config *OltConfig) (map[int][]*trafficRow, error) {
+	rows := make(map[int][]*trafficRow)
+	byIndex := make(map[string]*trafficRow)
+	olt := oltKey(snmp)
+	downBps, upBps, _ := ponCapacity(config.Port.Name)
+
+	read := func(column string, upstream bool) error {
+		if column == "" {
+			return nil
+		}
+		return walkColumn(snmp, column, func(pdu gosnmp.SnmpPDU) error {
+			id, ok := columnIndex(column, pdu.Name)
+			if !ok {
+				return nil
+			}
+			sample := counterSample{
+				value: gosnmp.ToBigInt(pdu.Value).Uint64(),
+				is64:  pdu.Type == gosnmp.Counter64,
+				at:    time.Now(),
+			}
+
+			// Rows of both columns share the index after the column OID
+			index := strings.TrimPrefix(pdu.Name, column)
+			row := byIndex[index]
+			if row == nil {
+				row = &trafficRow{gemport: rowGemport(index), known: true}
+				byIndex[index] = row
+				rows[id] = append(rows[id], row)
+			}
+
+			rate, known := 0.0, false
+			if prev, ok := trafficCounters.swap(olt+pdu.Name, sample); ok {
+				maxBps := downBps
+				if upstream {
+					maxBps = upBps
+				}
+				rate, known = counterRate(prev, sample, maxBps)
+				row.interval = sample.at.Sub(prev.at)
+			}
+			row.known = row.known && known
+			if upstream {
+				row.up, row.upBps = sample.value, rate
+			} else {
+				row.down, row.downBps = sample.value, rate
+			}
+			return nil
+		})
+	}
+
+	if err := read(config.OnuUpstreamOctetsOID, true); err != nil {
+		return nil, fmt.Errorf("SNMP walk failed: %w", snmpAuthError(err))
+	}
+	if err := read(config.OnuDownstreamOctetsOID, false); err != nil {
+		return nil, fmt.Errorf("SNMP walk failed: %w", snmpAuthError(err))
+	}
+	return rows, nil
+}
+
+// rowGemport returns the gemport of a counter row index (".onu.gemport"), or
+// 0 for ONU rows (".onu")
+func rowGemport(index string) int {
+	parts := strings.Split(strings.TrimPrefix(index, "."), ".")
+	if len(parts) < 2 {
+		return 0
+	}
+	gemport, _ := strconv.Atoi(parts[len(parts)-1])
+	return gemport
+}
+
+// add sums the counter rows of an ONU into its totals; gemport rows are
+// also listed on their own. The rates are only available when every row
+// has both.
+func (t *ONUTraffic) add(rows []*trafficRow) {
+	if len(rows) == 0 {
+		return
+	}
+	t.RateAvailable = true
+	for _, row := range rows {
+		t.UpstreamOctets += row.up
+		t.DownstreamOctets += row.down
+		t.UpstreamBps += row.upBps
+		t.DownstreamBps += row.downBps
+		t.RateAvailable = t.RateAvailable && row.known
+		t.Interval = max(t.Interval, row.interval)
+
+		if row.gemport > 0 {
+			t.Gemports = append(t.Gemports, GemportTraffic{
+				Gemport:          row.gemport,
+				UpstreamOctets:   row.up,
+				DownstreamOctets: row.down,
+				UpstreamBps:      row.upBps,
+				DownstreamBps:    row.downBps,
+			})
+		}
+	}
+	if !t.RateAvailable {
+		t.UpstreamBps, t.DownstreamBps = 0, 0
+		for i := range t.Gemports {
+			t.Gemports[i].UpstreamBps, t.Gemports[i].DownstreamBps = 0, 0
+		}
+	}
+	sort.Slice(t.Gemports, func(i, j int) bool { return t.Gemports[i].Gemport < t.Gemports[j].Gemport })
+}
@@ -0,0 +1,55 @@
+package olt
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCounterStoreSwap(t *testing.T) {
+	store := &counterStore{samples: make(map[string]counterSample)}
+	t0 := time.Now()
+	at := func(d time.Duration, v uint64) counterSample { return counterSample{value: v, at: t0.Add(d)} }
+
+	if _, ok := store.swap("row", at(0, 100)); ok {
+		t.Fatal("first sample returned a previous sample")
+	}
+	if _, ok := store.swap("row", at(200*time.Millisecond, 150)); ok {
+		t.Fatal("sample closer than the minimum interval returned a previous sample")
+	}
+	prev, ok := store.swap("row", at(5*time.Second, 600))
+	if !ok || prev.value != 100 {
+		t.Fatalf("swap returned %+v, %v; want the first sample", prev, ok)
+	}
+	if rate, ok := counterRate(prev, at(5*time.Second, 600), 2.488e9); !ok || rate != 800 {
+		t.Errorf("counterRate = %v, %v; want 800", rate, ok)
+	}
+	if _, ok := store.swap("row", at(5*time.Second+trafficSampleMaxAge+time.Second, 700)); ok {
+		t.Error("sample older than the maximum age was returned")
+	}
+}
+
+func TestCounterDelta(t *testing.T) {
+	t0 := time.Now()
+	at := func(d time.Duration, v uint64, is64 bool) counterSample {
+		return counterSample{value: v, is64: is64, at: t0.Add(d)}
+	}
+	tests := []struct {
+		prev, cur counterSample
+		want      uint64
+		ok        bool
+	}{
+		{at(0, 10, false), at(time.Second, 25, false), 15, true},
+		{at(0, math.MaxUint32-4, false), at(time.Second, 5, false), 10, true},
+		// a reset: taken as a wrap, 4.3 GB in 5s would exceed the GPON line rate
+		{at(0, 1000, false), at(5*time.Second, 5, false), 0, false},
+		{at(0, 100, true), at(time.Second, 5, true), 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := counterDelta(tt.prev, tt.cur, 2.488e9)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("counterDelta(%d, %d) = %d, %v; want %d, %v", tt.prev.value, tt.cur.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
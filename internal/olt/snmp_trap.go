@@ -0,0 +1,422 @@
+package olt
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// snmpTrapOIDOID is the SNMPv2-MIB snmpTrapOID.0 varbind naming a v2c/v3 trap
+const snmpTrapOIDOID = ".1.3.6.1.6.3.1.1.4.1.0"
+
+// DefaultTrapBufferSize is the number of recent trap events kept by default
+const DefaultTrapBufferSize = 1000
+
+// TrapEventType is the kind of event decoded from a trap
+type TrapEventType string
+
+// Event types OID profiles map trap OIDs to
+const (
+	TrapEventLOS         TrapEventType = "los"
+	TrapEventDyingGasp   TrapEventType = "dying_gasp"
+	TrapEventPowerOff    TrapEventType = "power_off"
+	TrapEventAuthFailure TrapEventType = "auth_failure"
+	TrapEventONUOnline   TrapEventType = "onu_online"
+	TrapEventONUOffline  TrapEventType = "onu_offline"
+	TrapEventRxPowerLow  TrapEventType = "rx_power_low"
+	TrapEventRxPowerHigh TrapEventType = "rx_power_high"
+	TrapEventUnknown     TrapEventType = "unknown" // trap OID not in the OID profile
+)
+
+// TrapEventTypes lists the event types traps are decoded into
+var TrapEventTypes = []TrapEventType{
+	TrapEventLOS, TrapEventDyingGasp, TrapEventPowerOff, TrapEventAuthFailure,
+	TrapEventONUOnline, TrapEventONUOffline, TrapEventRxPowerLow, TrapEventRxPowerHigh,
+	TrapEventUnknown,
+}
+
+// offlineReasonEvents refines ONU offline traps by their offline reason
+var offlineReasonEvents = map[string]TrapEventType{
+	"LOS":      TrapEventLOS,
+	"LOSi":     TrapEventLOS,
+	"LOFi":     TrapEventLOS,
+	"PowerOff": TrapEventPowerOff,
+	"AuthFail": TrapEventAuthFailure,
+}
+
+// TrapEvent is an event decoded from a trap or inform. The ONU fields are
+// set from the varbinds that are ONU table columns of the OID profile.
+type TrapEvent struct {
+	ID           uint64            `json:"id"`
+	Type         TrapEventType     `json:"type"`
+	Host         string            `json:"host"`
+	TrapOID      string            `json:"trap_oid"`
+	Version      string            `json:"version"`
+	Inform       bool              `json:"inform,omitempty"`
+	Rack         int               `json:"rack,omitempty"`
+	Shelf        int               `json:"shelf,omitempty"`
+	Board        int               `json:"board,omitempty"`
+	PON          int               `json:"pon,omitempty"`
+	ONU          int               `json:"onu_id,omitempty"`
+	Name         string            `json:"name,omitempty"`
+	SerialNumber string            `json:"serial_number,omitempty"`
+	Status       string            `json:"status,omitempty"`
+	Reason       string            `json:"reason,omitempty"`
+	RxPower      string            `json:"rx_power,omitempty"`
+	Variables    map[string]string `json:"variables"`
+	ReceivedAt   time.Time         `json:"received_at"`
+}
+
+// TrapEventFilter selects recent trap events; zero fields match any event
+type TrapEventFilter struct {
+	Host  string
+	Type  TrapEventType
+	Board int
+	PON   int
+	ONU   int
+	Since time.Time
+	Limit int
+}
+
+// TrapSinkConfig forwards the events of the listed types to a sink
+type TrapSinkConfig struct {
+	Sink   TrapSink
+	Events []TrapEventType // default: all
+}
+
+// TrapOptions configures a trap receiver
+type TrapOptions struct {
+	Communities []string           // v1/v2c communities accepted (default: any)
+	V3          *SNMPv3Credentials // USM user of v3 traps and informs
+	EngineID    string             // hex authoritative engine ID the v3 keys are localized with
+	BufferSize  int                // recent events kept (default: DefaultTrapBufferSize)
+	Sinks       []TrapSinkConfig
+
+	// Device returns the vendor, model, firmware and OID profile name of the
+	// OLT a trap comes from, selecting the OID profile used to decode it
+	Device func(host string) (vendor, model, firmware, oidProfile string)
+}
+
+// TrapSinkStats counts the events forwarded to a sink
+type TrapSinkStats struct {
+	Name      string `json:"name"`
+	Sent      uint64 `json:"sent"`
+	Failed    uint64 `json:"failed"`
+	Dropped   uint64 `json:"dropped"` // queue full
+	LastError string `json:"last_error,omitempty"`
+}
+
+// TrapStats counts the traps handled by a receiver
+type TrapStats struct {
+	Listen   string          `json:"listen"`
+	Received uint64          `json:"received"`
+	Rejected uint64          `json:"rejected"` // unknown community
+	Unknown  uint64          `json:"unknown"`  // trap OID not in the OID profile
+	Buffered int             `json:"buffered"`
+	Sinks    []TrapSinkStats `json:"sinks"`
+}
+
+// TrapReceiver listens for SNMP traps and informs, decodes them into events,
+// keeps the recent events and forwards them to its sinks
+type TrapReceiver struct {
+	opts     TrapOptions
+	listener *gosnmp.TrapListener
+	events   *trapBuffer
+	sinks    []*trapSinkQueue
+	addr     atomic.Value // string
+
+	received atomic.Uint64
+	rejected atomic.Uint64
+	unknown  atomic.Uint64
+}
+
+// NewTrapReceiver creates a trap receiver; Listen starts it
+func NewTrapReceiver(opts TrapOptions) (*TrapReceiver, error) {
+	params := &gosnmp.GoSNMP{
+		Port:      162,
+		Transport: "udp",
+		Version:   gosnmp.Version2c,
+		Timeout:   2 * time.Second,
+		Retries:   3,
+		MaxOids:   gosnmp.MaxOids,
+	}
+	if opts.V3 != nil {
+		if err := applySNMPv3(params, opts.V3); err != nil {
+			return nil, err
+		}
+		if opts.EngineID != "" {
+			engineID, err := hex.DecodeString(strings.TrimPrefix(opts.EngineID, "0x"))
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid engine ID %q", ErrSNMPConfig, opts.EngineID)
+			}
+			params.SecurityParameters.(*gosnmp.UsmSecurityParameters).AuthoritativeEngineID = string(engineID)
+		}
+	}
+
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultTrapBufferSize
+	}
+	r := &TrapReceiver{
+		opts:     opts,
+		listener: gosnmp.NewTrapListener(),
+		events:   &trapBuffer{size: opts.BufferSize},
+	}
+	r.listener.Params = params
+	r.listener.OnNewTrap = r.handle
+
+	for _, s := range opts.Sinks {
+		for _, t := range s.Events {
+			if !slices.Contains(TrapEventTypes, t) {
+				return nil, fmt.Errorf("sink %s: unknown event type %q", s.Sink.Name(), t)
+			}
+		}
+		r.sinks = append(r.sinks, newTrapSinkQueue(s))
+	}
+	return r, nil
+}
+
+// Listen receives traps on a UDP address such as 0.0.0.0:162 until Close
+func (r *TrapReceiver) Listen(addr string) error {
+	r.addr.Store(addr)
+	for _, q := range r.sinks {
+		go q.run()
+	}
+	return r.listener.Listen(addr)
+}
+
+// Close stops listening and forwarding
+func (r *TrapReceiver) Close() {
+	r.listener.Close()
+	for _, q := range r.sinks {
+		q.close()
+	}
+}
+
+// Events returns the recent events matching a filter, newest first
+func (r *TrapReceiver) Events(filter TrapEventFilter) []TrapEvent {
+	return r.events.list(filter)
+}
+
+// Stats returns the trap and sink counters
+func (r *TrapReceiver) Stats() TrapStats {
+	addr, _ := r.addr.Load().(string)
+	stats := TrapStats{
+		Listen:   addr,
+		Received: r.received.Load(),
+		Rejected: r.rejected.Load(),
+		Unknown:  r.unknown.Load(),
+		Buffered: r.events.len(),
+		Sinks:    make([]TrapSinkStats, 0, len(r.sinks)),
+	}
+	for _, q := range r.sinks {
+		stats.Sinks = append(stats.Sinks, q.stats())
+	}
+	return stats
+}
+
+// handle decodes a trap, stores the event and queues it to the sinks
+func (r *TrapReceiver) handle(packet *gosnmp.SnmpPacket, addr *net.UDPAddr) {
+	r.received.Add(1)
+	if packet.Version != gosnmp.Version3 && len(r.opts.Communities) > 0 &&
+		!slices.Contains(r.opts.Communities, packet.Community) {
+		r.rejected.Add(1)
+		return
+	}
+
+	event := r.events.add(r.decode(packet, addr.IP.String()))
+	if event.Type == TrapEventUnknown {
+		r.unknown.Add(1)
+	}
+	for _, q := range r.sinks {
+		q.push(event)
+	}
+}
+
+// decode turns a trap into an event with the OID profile of its OLT
+func (r *TrapReceiver) decode(packet *gosnmp.SnmpPacket, host string) TrapEvent {
+	event := TrapEvent{
+		Type:       TrapEventUnknown,
+		Host:       host,
+		Version:    packet.Version.String(),
+		Inform:     packet.PDUType == gosnmp.InformRequest,
+		Variables:  make(map[string]string, len(packet.Variables)),
+		ReceivedAt: time.Now(),
+	}
+	if packet.Version == gosnmp.Version1 {
+		// RFC 3584: the v2 trap OID of a v1 enterprise-specific trap
+		event.TrapOID = fmt.Sprintf(".%s.0.%d", strings.TrimPrefix(packet.Enterprise, "."), packet.SpecificTrap)
+	}
+
+	var vendor, model, firmware, name string
+	if r.opts.Device != nil {
+		vendor, model, firmware, name = r.opts.Device(host)
+	}
+	profile, err := SelectOIDProfile(vendor, model, firmware, name)
+	if err != nil {
+		profile, _ = SelectOIDProfile(vendor, "", "", "")
+	}
+	driver, _ := GetDriver(vendor)
+
+	for _, pdu := range packet.Variables {
+		oid := "." + strings.TrimPrefix(pdu.Name, ".")
+		switch oid {
+		case snmpTrapOIDOID:
+			if trapOID, ok := pdu.Value.(string); ok {
+				event.TrapOID = "." + strings.TrimPrefix(trapOID, ".")
+			}
+			continue
+		case sysUpTimeOID:
+			continue
+		}
+		event.Variables[oid] = trapValue(pdu)
+		if driver != nil {
+			event.setColumn(profile, driver, oid, pdu)
+		}
+	}
+
+	if t, ok := profile.Traps[event.TrapOID]; ok {
+		event.Type = TrapEventType(t)
+	}
+	if t, ok := offlineReasonEvents[event.Reason]; ok && event.Type == TrapEventONUOffline {
+		event.Type = t
+	}
+	return event
+}
+
+// setColumn sets the ONU and the value of a varbind that is a row of an ONU
+// table column of the OID profile
+func (e *TrapEvent) setColumn(profile OIDProfile, driver Driver, oid string, pdu gosnmp.SnmpPDU) {
+	for column, template := range profile.Columns {
+		if strings.HasPrefix(column, "pon_") || isSystemColumn(column) {
+			continue
+		}
+		indexes, onu, ok := matchColumn(template, oid)
+		if !ok {
+			continue
+		}
+		port, ok := driver.PONPortFromIndexes(indexes)
+		if !ok {
+			return
+		}
+		e.Rack, e.Shelf, e.Board, e.PON, e.ONU = port.Rack, port.Shelf, port.Slot, port.Port, onu
+
+		switch column {
+		case "name":
+			e.Name = ExtractName(pdu.Value)
+		case "serial_number":
+			e.SerialNumber = ExtractSerialNumber(pdu.Value)
+		case "status":
+			e.Status = ExtractAndGetStatus(pdu.Value)
+		case "last_offline_reason":
+			e.Reason = ExtractLastOfflineReason(pdu.Value)
+		case "rx_power":
+			if rx, err := ConvertAndMultiply(pdu.Value); err == nil {
+				e.RxPower = rx
+			}
+		}
+		return
+	}
+}
+
+// matchColumn matches an OID against a column OID with placeholders,
+// returning the placeholder values and the ONU ID of the row
+func matchColumn(template, oid string) (map[string]int, int, bool) {
+	columnArcs := strings.Split(strings.TrimPrefix(template, "."), ".")
+	arcs := strings.Split(strings.TrimPrefix(oid, "."), ".")
+	if len(arcs) <= len(columnArcs) || !strings.Contains(template, "{") {
+		return nil, 0, false
+	}
+
+	indexes := make(map[string]int)
+	for i, arc := range columnArcs {
+		if m := placeholderRE.FindStringSubmatch(arc); m != nil {
+			v, err := strconv.Atoi(arcs[i])
+			if err != nil {
+				return nil, 0, false
+			}
+			indexes[m[1]] = v
+		} else if arc != arcs[i] {
+			return nil, 0, false
+		}
+	}
+	onu, err := strconv.Atoi(arcs[len(columnArcs)])
+	return indexes, onu, err == nil
+}
+
+// trapValue returns a varbind value as text
+func trapValue(pdu gosnmp.SnmpPDU) string {
+	switch v := pdu.Value.(type) {
+	case []byte:
+		return ExtractName(v)
+	case string:
+		return v
+	case nil:
+		return ""
+	}
+	return gosnmp.ToBigInt(pdu.Value).String()
+}
+
+// trapBuffer is a ring buffer of the recent trap events
+type trapBuffer struct {
+	mu     sync.Mutex
+	size   int
+	events []TrapEvent
+	next   int // oldest event once the buffer is full
+	seq    uint64
+}
+
+// add numbers an event and stores it, replacing the oldest when full
+func (b *trapBuffer) add(event TrapEvent) TrapEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event.ID = b.seq
+	if len(b.events) < b.size {
+		b.events = append(b.events, event)
+	} else {
+		b.events[b.next] = event
+		b.next = (b.next + 1) % b.size
+	}
+	return event
+}
+
+// len returns the number of buffered events
+func (b *trapBuffer) len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.events)
+}
+
+// list returns the events matching a filter, newest first
+func (b *trapBuffer) list(f TrapEventFilter) []TrapEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make([]TrapEvent, 0)
+	for i := len(b.events) - 1; i >= 0; i-- {
+		e := b.events[(b.next+i)%len(b.events)]
+		switch {
+		case f.Host != "" && e.Host != f.Host,
+			f.Type != "" && e.Type != f.Type,
+			f.Board > 0 && e.Board != f.Board,
+			f.PON > 0 && e.PON != f.PON,
+			f.ONU > 0 && e.ONU != f.ONU,
+			!f.Since.IsZero() && e.ReceivedAt.Before(f.Since):
+			continue
+		}
+		events = append(events, e)
+		if f.Limit > 0 && len(events) == f.Limit {
+			break
+		}
+	}
+	return events
+}
@@ -0,0 +1,204 @@
+package olt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+func TestTrapDecode(t *testing.T) {
+	const (
+		zteONU   = ".1.3.6.1.4.1.3902.1082.500.10.2.3"
+		ifIndex  = ".285278721" // 1/1/2/1
+		nameOID  = zteONU + ".3.1.2" + ifIndex + ".5"
+		stateOID = zteONU + ".8.1.4" + ifIndex + ".5"
+		causeOID = zteONU + ".8.1.7" + ifIndex + ".5"
+		typeOID  = ".1.3.6.1.4.1.3902.1012.3.50.11.2.1.17.268566784.5" // {ext_if_index} of slot 2 port 1
+	)
+	v2c := func(pduType gosnmp.PDUType, trapOID string, vars ...gosnmp.SnmpPDU) *gosnmp.SnmpPacket {
+		return &gosnmp.SnmpPacket{
+			Version: gosnmp.Version2c,
+			PDUType: pduType,
+			Variables: append([]gosnmp.SnmpPDU{
+				{Name: sysUpTimeOID, Type: gosnmp.TimeTicks, Value: uint32(1200)},
+				{Name: snmpTrapOIDOID, Type: gosnmp.ObjectIdentifier, Value: trapOID},
+			}, vars...),
+		}
+	}
+	name := gosnmp.SnmpPDU{Name: nameOID, Type: gosnmp.OctetString, Value: []byte("onu-5")}
+	state := gosnmp.SnmpPDU{Name: stateOID, Type: gosnmp.Integer, Value: 7}
+	cause := func(reason int) gosnmp.SnmpPDU {
+		return gosnmp.SnmpPDU{Name: causeOID, Type: gosnmp.Integer, Value: reason}
+	}
+
+	tests := []struct {
+		name   string
+		packet *gosnmp.SnmpPacket
+		want   TrapEvent
+	}{
+		{
+			"v2c online",
+			v2c(gosnmp.SNMPv2Trap, zteONU+".0.1", name),
+			TrapEvent{
+				Type: TrapEventONUOnline, TrapOID: zteONU + ".0.1", Version: gosnmp.Version2c.String(),
+				Rack: 1, Shelf: 1, Board: 2, PON: 1, ONU: 5, Name: "onu-5",
+				Variables: map[string]string{nameOID: "onu-5"},
+			},
+		},
+		{
+			"v2c inform on a 1012 column",
+			v2c(gosnmp.InformRequest, zteONU+".0.5", gosnmp.SnmpPDU{Name: typeOID, Type: gosnmp.OctetString, Value: []byte("F660")}),
+			TrapEvent{
+				Type: TrapEventAuthFailure, TrapOID: zteONU + ".0.5", Version: gosnmp.Version2c.String(), Inform: true,
+				Rack: 1, Shelf: 1, Board: 2, PON: 1, ONU: 5,
+				Variables: map[string]string{typeOID: "F660"},
+			},
+		},
+		{
+			"v1 enterprise-specific trap",
+			&gosnmp.SnmpPacket{
+				Version:   gosnmp.Version1,
+				PDUType:   gosnmp.Trap,
+				SnmpTrap:  gosnmp.SnmpTrap{Enterprise: zteONU, GenericTrap: 6, SpecificTrap: 4},
+				Variables: []gosnmp.SnmpPDU{name},
+			},
+			TrapEvent{
+				Type: TrapEventDyingGasp, TrapOID: zteONU + ".0.4", Version: gosnmp.Version1.String(),
+				Rack: 1, Shelf: 1, Board: 2, PON: 1, ONU: 5, Name: "onu-5",
+				Variables: map[string]string{nameOID: "onu-5"},
+			},
+		},
+		{
+			"offline by power off",
+			v2c(gosnmp.SNMPv2Trap, zteONU+".0.2", state, cause(9)),
+			TrapEvent{
+				Type: TrapEventPowerOff, TrapOID: zteONU + ".0.2", Version: gosnmp.Version2c.String(),
+				Rack: 1, Shelf: 1, Board: 2, PON: 1, ONU: 5, Status: "Offline", Reason: "PowerOff",
+				Variables: map[string]string{stateOID: "7", causeOID: "9"},
+			},
+		},
+		{
+			"offline by LOSi",
+			v2c(gosnmp.SNMPv2Trap, zteONU+".0.2", cause(3)),
+			TrapEvent{
+				Type: TrapEventLOS, TrapOID: zteONU + ".0.2", Version: gosnmp.Version2c.String(),
+				Rack: 1, Shelf: 1, Board: 2, PON: 1, ONU: 5, Reason: "LOSi",
+				Variables: map[string]string{causeOID: "3"},
+			},
+		},
+		{
+			"offline for another reason",
+			v2c(gosnmp.SNMPv2Trap, zteONU+".0.2", cause(5)),
+			TrapEvent{
+				Type: TrapEventONUOffline, TrapOID: zteONU + ".0.2", Version: gosnmp.Version2c.String(),
+				Rack: 1, Shelf: 1, Board: 2, PON: 1, ONU: 5, Reason: "sfi",
+				Variables: map[string]string{causeOID: "5"},
+			},
+		},
+		{
+			"reason of a trap other than offline",
+			v2c(gosnmp.SNMPv2Trap, zteONU+".0.1", cause(9)),
+			TrapEvent{
+				Type: TrapEventONUOnline, TrapOID: zteONU + ".0.1", Version: gosnmp.Version2c.String(),
+				Rack: 1, Shelf: 1, Board: 2, PON: 1, ONU: 5, Reason: "PowerOff",
+				Variables: map[string]string{causeOID: "9"},
+			},
+		},
+		{
+			"unknown trap OID",
+			v2c(gosnmp.SNMPv2Trap, ".1.3.6.1.4.1.9999.0.1", gosnmp.SnmpPDU{Name: ".1.3.6.1.4.1.9999.1", Type: gosnmp.Integer, Value: 42}),
+			TrapEvent{
+				Type: TrapEventUnknown, TrapOID: ".1.3.6.1.4.1.9999.0.1", Version: gosnmp.Version2c.String(),
+				Variables: map[string]string{".1.3.6.1.4.1.9999.1": "42"},
+			},
+		},
+	}
+
+	r := &TrapReceiver{opts: TrapOptions{
+		Device: func(host string) (string, string, string, string) { return "zte", "C300", "V2.1", "" },
+	}}
+	for _, tt := range tests {
+		got := r.decode(tt.packet, "192.0.2.1")
+		if got.ReceivedAt.IsZero() {
+			t.Errorf("%s: ReceivedAt not set", tt.name)
+		}
+		got.ReceivedAt = time.Time{}
+		tt.want.Host = "192.0.2.1"
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: decode() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchColumn(t *testing.T) {
+	tests := []struct {
+		template, oid string
+		indexes       map[string]int
+		onu           int
+		ok            bool
+	}{
+		{".1.3.6.{if_index}", ".1.3.6.285278721.5", map[string]int{"if_index": 285278721}, 5, true},
+		{".1.3.6.{if_index}", "1.3.6.285278721.5", map[string]int{"if_index": 285278721}, 5, true},
+		{".1.3.6.{if_index}", ".1.3.6.285278721.5.2", map[string]int{"if_index": 285278721}, 5, true},
+		{".1.{slot}.6.{port}", ".1.2.6.4.7", map[string]int{"slot": 2, "port": 4}, 7, true},
+		{".1.3.6.{if_index}", ".1.3.7.285278721.5", nil, 0, false},
+		{".1.3.6.{if_index}", ".1.3.6.285278721", nil, 0, false},
+		{".1.3.6.{if_index}", ".1.3.6", nil, 0, false},
+		{".1.3.6.1", ".1.3.6.1.5", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		indexes, onu, ok := matchColumn(tt.template, tt.oid)
+		if ok != tt.ok || onu != tt.onu || (ok && !reflect.DeepEqual(indexes, tt.indexes)) {
+			t.Errorf("matchColumn(%q, %q) = %v, %d, %v; want %v, %d, %v", tt.template, tt.oid, indexes, onu, ok, tt.indexes, tt.onu, tt.ok)
+		}
+	}
+}
+
+func TestTrapBuffer(t *testing.T) {
+	t0 := time.Now()
+	b := &trapBuffer{size: 4}
+	events := []TrapEvent{
+		{Host: "olt-a", Type: TrapEventLOS, Board: 1, PON: 1, ONU: 1},
+		{Host: "olt-a", Type: TrapEventLOS, Board: 2, PON: 1, ONU: 1},
+		{Host: "olt-b", Type: TrapEventDyingGasp, Board: 2, PON: 3, ONU: 7},
+		{Host: "olt-a", Type: TrapEventONUOnline, Board: 2, PON: 1, ONU: 1},
+		{Host: "olt-b", Type: TrapEventLOS, Board: 2, PON: 3, ONU: 8},
+		{Host: "olt-a", Type: TrapEventDyingGasp, Board: 2, PON: 1, ONU: 2},
+	}
+	for i, e := range events {
+		e.ReceivedAt = t0.Add(time.Duration(i) * time.Second)
+		if got := b.add(e); got.ID != uint64(i+1) {
+			t.Errorf("event %d got ID %d", i+1, got.ID)
+		}
+	}
+	if b.len() != 4 {
+		t.Errorf("len() = %d, want 4", b.len())
+	}
+
+	tests := []struct {
+		name   string
+		filter TrapEventFilter
+		want   []uint64
+	}{
+		{"all, newest first after wrapping", TrapEventFilter{}, []uint64{6, 5, 4, 3}},
+		{"host", TrapEventFilter{Host: "olt-b"}, []uint64{5, 3}},
+		{"type", TrapEventFilter{Type: TrapEventLOS}, []uint64{5}},
+		{"board and PON", TrapEventFilter{Board: 2, PON: 1}, []uint64{6, 4}},
+		{"ONU", TrapEventFilter{PON: 3, ONU: 7}, []uint64{3}},
+		{"since", TrapEventFilter{Since: t0.Add(4 * time.Second)}, []uint64{6, 5}},
+		{"limit", TrapEventFilter{Limit: 3}, []uint64{6, 5, 4}},
+		{"no match", TrapEventFilter{Host: "olt-c"}, []uint64{}},
+	}
+	for _, tt := range tests {
+		ids := []uint64{}
+		for _, e := range b.list(tt.filter) {
+			ids = append(ids, e.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("%s: list() = %v, want %v", tt.name, ids, tt.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package olt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// trapSinkQueueSize is the number of events waiting for a sink before new
+// ones are dropped, so that a slow sink never blocks the trap listener
+const trapSinkQueueSize = 256
+
+// TrapSink receives the events decoded from traps
+type TrapSink interface {
+	// Name identifies the sink in the receiver stats
+	Name() string
+
+	// Send delivers one event
+	Send(event TrapEvent) error
+}
+
+// webhookSink posts events as JSON to an HTTP endpoint
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhookSink returns a sink posting each event as JSON to url with the
+// given headers (e.g. Authorization); a non-2xx response is a failure
+func NewWebhookSink(url string, headers map[string]string, timeout time.Duration) TrapSink {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &webhookSink{url: url, headers: headers, client: &http.Client{Timeout: timeout}}
+}
+
+func (s *webhookSink) Name() string { return "webhook " + s.url }
+
+func (s *webhookSink) Send(event TrapEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// logSink writes events to the server log
+type logSink struct{}
+
+// NewLogSink returns a sink writing one line per event to the server log
+func NewLogSink() TrapSink { return logSink{} }
+
+func (logSink) Name() string { return "log" }
+
+func (logSink) Send(e TrapEvent) error {
+	target := e.TrapOID
+	if e.ONU > 0 {
+		target = fmt.Sprintf("ONU %d/%d/%d:%d", e.Shelf, e.Board, e.PON, e.ONU)
+		if e.Name != "" {
+			target += " (" + e.Name + ")"
+		}
+	}
+	if e.Reason != "" {
+		target += ", reason " + e.Reason
+	}
+	if e.RxPower != "" {
+		target += ", rx " + e.RxPower + " dBm"
+	}
+	log.Printf("📟 SNMP trap from %s: %s %s", e.Host, e.Type, target)
+	return nil
+}
+
+// fileSink appends events as JSON lines to a file
+type fileSink struct {
+	path string
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink returns a sink appending each event as one JSON line to path
+func NewFileSink(path string) (TrapSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trap event file: %w", err)
+	}
+	return &fileSink{path: path, file: file}, nil
+}
+
+func (s *fileSink) Name() string { return "file " + s.path }
+
+func (s *fileSink) Send(event TrapEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// trapSinkQueue delivers the events of the selected types to a sink from
+// its own goroutine
+type trapSinkQueue struct {
+	sink   TrapSink
+	events []TrapEventType // nil: all
+	queue  chan TrapEvent
+	once   sync.Once
+
+	sent    atomic.Uint64
+	failed  atomic.Uint64
+	dropped atomic.Uint64
+
+	mu        sync.Mutex
+	lastError string
+}
+
+func newTrapSinkQueue(cfg TrapSinkConfig) *trapSinkQueue {
+	return &trapSinkQueue{
+		sink:   cfg.Sink,
+		events: cfg.Events,
+		queue:  make(chan TrapEvent, trapSinkQueueSize),
+	}
+}
+
+// push queues an event of a selected type, dropping it when the queue is full
+func (q *trapSinkQueue) push(event TrapEvent) {
+	if len(q.events) > 0 && !slices.Contains(q.events, event.Type) {
+		return
+	}
+	select {
+	case q.queue <- event:
+	default:
+		q.dropped.Add(1)
+	}
+}
+
+// run delivers the queued events until the queue is closed
+func (q *trapSinkQueue) run() {
+	for event := range q.queue {
+		if err := q.sink.Send(event); err != nil {
+			q.failed.Add(1)
+			q.mu.Lock()
+			q.lastError = err.Error()
+			q.mu.Unlock()
+			continue
+		}
+		q.sent.Add(1)
+	}
+}
+
+// close stops accepting events; queued events are still delivered
+func (q *trapSinkQueue) close() {
+	q.once.Do(func() { close(q.queue) })
+}
+
+func (q *trapSinkQueue) stats() TrapSinkStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return TrapSinkStats{
+		Name:      q.sink.Name(),
+		Sent:      q.sent.Load(),
+		Failed:    q.failed.Load(),
+		Dropped:   q.dropped.Load(),
+		LastError: q.lastError,
+	}
+}
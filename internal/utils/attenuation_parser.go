@@ -1,9 +1,7 @@
 package utils
 
 import (
-	"fmt"
 	"regexp"
-	"strconv"
 	"strings"
 )
 
@@ -20,6 +18,7 @@ type AttenuationData struct {
 	ONUTxPower  float64 `json:"onu_tx_power_dbm"`
 	Attenuation float64 `json:"attenuation_db"`
 	Status      string  `json:"status"`
+	RxStatus    string  `json:"rx_status,omitempty"` // ONU rx power band, when the down direction was parsed
 	RawOutput   string  `json:"raw_output,omitempty"`
 }
 
@@ -44,7 +43,6 @@ func ParseAttenuationOutput(host string, board, pon, onu int, rawOutput string)
 		data.OLTRxPower = upData.OLTRxPower
 		data.ONUTxPower = upData.ONUTxPower
 		data.Attenuation = upData.Attenuation
-		data.Status = determineStatus(upData.Attenuation)
 	}
 
 	// Parse down direction (downstream)
@@ -61,13 +59,24 @@ func ParseAttenuationOutput(host string, board, pon, onu int, rawOutput string)
 			data.OLTTxPower = downData.OLTTxPower
 			data.ONURxPower = downData.ONURxPower
 			data.Attenuation = downData.Attenuation
-			data.Status = determineStatus(downData.Attenuation)
 		}
 	}
 
+	data.Classify(DefaultOpticalThresholds)
 	return data
 }
 
+// Classify sets the attenuation and rx power statuses using a threshold profile
+func (d *AttenuationData) Classify(t OpticalThresholds) {
+	if d.Direction == "" {
+		return
+	}
+	d.Status = t.AttenuationStatus(d.Attenuation)
+	if d.Direction != "up" {
+		d.RxStatus = t.RxPowerStatus(d.ONURxPower)
+	}
+}
+
 // DirectionData represents parsed data for one direction
 type DirectionData struct {
 	OLTRxPower  float64
@@ -77,59 +86,37 @@ type DirectionData struct {
 	Attenuation float64
 }
 
+// attenuationFSM parses the output of "show pon power attenuation"
+var attenuationFSM = MustLoadTextFSM("zte_show_pon_power_attenuation")
+
 // parseDirection parses data for specific direction (up/down)
 func parseDirection(output, direction string) *DirectionData {
-	// Pattern to match the attenuation output lines
-	// Example: "up      Rx :-28.827(dbm)      Tx:2.200(dbm)        31.027(dB)"
-	pattern := fmt.Sprintf(`(?i)%s\s+Rx\s*:(?P<rx>[-\d.]+)\s*\(dbm\)\s*Tx\s*:(?P<tx>[-\d.]+)\s*\(dbm\)\s+(?P<att>[-\d.]+)\s*\(db\)`, direction)
-
-	re := regexp.MustCompile(pattern)
-	matches := re.FindStringSubmatch(output)
-	if matches == nil {
+	records, err := attenuationFSM.Parse(output)
+	if err != nil {
 		return nil
 	}
 
-	// Get named subexpression indices
-	names := re.SubexpNames()
-	result := make(map[string]string)
-	for i, match := range matches {
-		if i > 0 && names[i] != "" {
-			result[names[i]] = match
-		}
-	}
-
-	data := &DirectionData{}
-
-	// Parse Rx power
-	if rxStr, ok := result["rx"]; ok {
-		if rx, err := strconv.ParseFloat(rxStr, 64); err == nil {
-			if direction == "up" {
-				data.OLTRxPower = rx
-			} else {
-				data.ONURxPower = rx
-			}
+	for _, rec := range records {
+		if !strings.EqualFold(rec.String("Direction"), direction) {
+			continue
 		}
-	}
-
-	// Parse Tx power
-	if txStr, ok := result["tx"]; ok {
-		if tx, err := strconv.ParseFloat(txStr, 64); err == nil {
-			if direction == "up" {
-				data.ONUTxPower = tx
-			} else {
-				data.OLTTxPower = tx
-			}
-		}
-	}
 
-	// Parse attenuation
-	if attStr, ok := result["att"]; ok {
-		if att, err := strconv.ParseFloat(attStr, 64); err == nil {
-			data.Attenuation = att
+		data := &DirectionData{}
+		data.OLTRxPower, _ = rec.Float("OltRx")
+		data.OLTTxPower, _ = rec.Float("OltTx")
+		data.ONURxPower, _ = rec.Float("OnuRx")
+		data.ONUTxPower, _ = rec.Float("OnuTx")
+		data.Attenuation, _ = rec.Float("Attenuation")
+
+		// Some firmware prints the down line as "Rx:<onu> Tx:<olt>"
+		if direction == "down" && rec.String("OnuRx") == "" {
+			data.ONURxPower, data.OLTTxPower = data.OLTRxPower, data.ONUTxPower
+			data.OLTRxPower, data.ONUTxPower = 0, 0
 		}
+		return data
 	}
 
-	return data
+	return nil
 }
 
 // cleanOutput removes ANSI escape sequences and extra whitespace
@@ -154,23 +141,6 @@ func cleanOutput(output string) string {
 	return strings.Join(cleanLines, "\n")
 }
 
-// determineStatus determines the status based on attenuation value
-func determineStatus(attenuation float64) string {
-	if attenuation < 0 {
-		return "error"
-	} else if attenuation <= 10 {
-		return "excellent"
-	} else if attenuation <= 15 {
-		return "good"
-	} else if attenuation <= 25 {
-		return "normal"
-	} else if attenuation <= 30 {
-		return "warning"
-	} else {
-		return "critical"
-	}
-}
-
 // GetStatusDescription returns human readable status description
 func GetStatusDescription(status string) string {
 	switch status {
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ONUHistoryEvent represents one entry of the online/offline history of an ONU
+type ONUHistoryEvent struct {
+	Index       int    `json:"index"`
+	OnlineTime  string `json:"online_time"`            // authpass time
+	OfflineTime string `json:"offline_time,omitempty"` // empty while the session is still online
+	Cause       string `json:"cause,omitempty"`
+	Online      bool   `json:"online"`
+}
+
+// ONUDetail represents parsed show gpon onu detail-info output
+type ONUDetail struct {
+	Host                  string            `json:"host"`
+	Interface             string            `json:"interface"`
+	Board                 int               `json:"board"`
+	PON                   int               `json:"pon"`
+	ONU                   int               `json:"onu"`
+	Name                  string            `json:"name"`
+	Type                  string            `json:"type"`
+	State                 string            `json:"state"`
+	AdminState            string            `json:"admin_state"`
+	PhaseState            string            `json:"phase_state"`
+	ConfigState           string            `json:"config_state"`
+	AuthMode              string            `json:"auth_mode"`
+	SerialNumber          string            `json:"serial_number"`
+	Password              string            `json:"password,omitempty"`
+	Description           string            `json:"description"`
+	VendorID              string            `json:"vendor_id"`
+	VersionID             string            `json:"version_id"`
+	DistanceM             int               `json:"distance_m"`
+	OnlineDuration        string            `json:"online_duration"`
+	OnlineDurationSeconds int64             `json:"online_duration_seconds"`
+	History               []ONUHistoryEvent `json:"history"`
+	RawOutput             string            `json:"raw_output,omitempty"`
+}
+
+var (
+	// onuDetailFSM and onuHistoryFSM parse the output of "show gpon onu detail-info"
+	onuDetailFSM  = MustLoadTextFSM("zte_show_gpon_onu_detail_info")
+	onuHistoryFSM = MustLoadTextFSM("zte_show_gpon_onu_detail_info_history")
+
+	durationPartRE = regexp.MustCompile(`(\d+)\s*([dhms])`)
+)
+
+// zeroTime is printed by ZTE for unused history slots and ONUs still online
+const zeroTime = "0000-00-00 00:00:00"
+
+// ParseONUDetailOutput parses the raw output from show gpon onu detail-info command
+func ParseONUDetailOutput(host string, rawOutput string) *ONUDetail {
+	data := &ONUDetail{
+		Host:      host,
+		History:   []ONUHistoryEvent{},
+		RawOutput: rawOutput,
+	}
+
+	output := cleanOutput(rawOutput)
+
+	if records, err := onuDetailFSM.Parse(output); err == nil && len(records) > 0 {
+		rec := records[0]
+		data.Interface = rec.String("Interface")
+		data.Name = rec.String("Name")
+		data.Type = rec.String("Type")
+		data.State = rec.String("State")
+		data.AdminState = rec.String("AdminState")
+		data.PhaseState = rec.String("PhaseState")
+		data.ConfigState = rec.String("ConfigState")
+		data.AuthMode = rec.String("AuthMode")
+		data.SerialNumber = rec.String("SerialNumber")
+		data.Password = rec.String("Password")
+		data.Description = rec.String("Description")
+		data.VendorID = rec.String("VendorID")
+		data.VersionID = rec.String("VersionID")
+		data.DistanceM = rec.Int("Distance")
+		data.OnlineDuration = rec.String("OnlineDuration")
+		data.OnlineDurationSeconds = ParseDuration(data.OnlineDuration)
+		_, data.Board, data.PON, data.ONU = ParseONUIndex(data.Interface)
+	}
+
+	if records, err := onuHistoryFSM.Parse(output); err == nil {
+		for _, rec := range records {
+			online := rec.String("AuthpassTime")
+			if online == zeroTime {
+				continue // unused slot
+			}
+
+			event := ONUHistoryEvent{
+				Index:      rec.Int("Index"),
+				OnlineTime: online,
+				Cause:      rec.String("Cause"),
+			}
+			if offline := rec.String("OfflineTime"); offline != zeroTime {
+				event.OfflineTime = offline
+			} else {
+				event.Online = true
+			}
+			data.History = append(data.History, event)
+		}
+	}
+
+	return data
+}
+
+// ParseDuration converts a ZTE duration such as "1d 10h 23m 5s" to seconds
+func ParseDuration(s string) int64 {
+	var total int64
+	for _, m := range durationPartRE.FindAllStringSubmatch(strings.ToLower(s), -1) {
+		n, _ := strconv.ParseInt(m[1], 10, 64)
+		switch m[2] {
+		case "d":
+			total += n * 86400
+		case "h":
+			total += n * 3600
+		case "m":
+			total += n * 60
+		case "s":
+			total += n
+		}
+	}
+	return total
+}
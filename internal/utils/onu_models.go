@@ -0,0 +1,127 @@
+package utils
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// onuModelsJSON is the built-in ONU model catalog
+//
+//go:embed onu_models.json
+var onuModelsJSON []byte
+
+// ONUModel describes an ONU model family in the catalog
+type ONUModel struct {
+	Pattern     string `json:"pattern"` // matched case-insensitively against the reported model
+	Vendor      string `json:"vendor"`
+	Technology  string `json:"technology,omitempty"`
+	Description string `json:"description"`
+}
+
+// onuModelFile is the layout of an ONU model catalog file
+type onuModelFile struct {
+	Models []ONUModel `json:"models"`
+}
+
+var (
+	onuModelsMu sync.RWMutex
+	onuModels   = mustParseONUModels(onuModelsJSON)
+)
+
+// LookupONUModel returns the catalog entry with the longest pattern contained in model
+func LookupONUModel(model string) (ONUModel, bool) {
+	upper := strings.ToUpper(model)
+
+	onuModelsMu.RLock()
+	defer onuModelsMu.RUnlock()
+
+	// Models are sorted by pattern length, so the most specific match wins
+	for _, m := range onuModels {
+		if strings.Contains(upper, m.Pattern) {
+			return m, true
+		}
+	}
+	return ONUModel{}, false
+}
+
+// ONUModels returns the ONU model catalog
+func ONUModels() []ONUModel {
+	onuModelsMu.RLock()
+	defer onuModelsMu.RUnlock()
+	return append([]ONUModel(nil), onuModels...)
+}
+
+// LoadONUModelCatalog merges the models of a JSON catalog file into the
+// built-in catalog; entries with an existing pattern replace it
+func LoadONUModelCatalog(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read ONU model catalog %s: %w", path, err)
+	}
+	extra, err := parseONUModels(data)
+	if err != nil {
+		return fmt.Errorf("invalid ONU model catalog %s: %w", path, err)
+	}
+
+	onuModelsMu.Lock()
+	defer onuModelsMu.Unlock()
+
+	byPattern := make(map[string]ONUModel, len(onuModels)+len(extra))
+	for _, m := range onuModels {
+		byPattern[m.Pattern] = m
+	}
+	for _, m := range extra {
+		byPattern[m.Pattern] = m
+	}
+
+	merged := make([]ONUModel, 0, len(byPattern))
+	for _, m := range byPattern {
+		merged = append(merged, m)
+	}
+	onuModels = sortONUModels(merged)
+	return nil
+}
+
+// parseONUModels decodes a catalog and orders it for matching
+func parseONUModels(data []byte) ([]ONUModel, error) {
+	var file onuModelFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	for i := range file.Models {
+		m := &file.Models[i]
+		m.Pattern = strings.ToUpper(strings.TrimSpace(m.Pattern))
+		if m.Pattern == "" {
+			return nil, fmt.Errorf("model %d has no pattern", i+1)
+		}
+		if m.Description == "" {
+			m.Description = strings.TrimSpace(m.Vendor + " " + m.Pattern + " ONU")
+		}
+	}
+	return sortONUModels(file.Models), nil
+}
+
+// mustParseONUModels parses the embedded catalog
+func mustParseONUModels(data []byte) []ONUModel {
+	models, err := parseONUModels(data)
+	if err != nil {
+		panic("utils: invalid embedded ONU model catalog: " + err.Error())
+	}
+	return models
+}
+
+// sortONUModels orders models by descending pattern length, then pattern
+func sortONUModels(models []ONUModel) []ONUModel {
+	sort.Slice(models, func(i, j int) bool {
+		if len(models[i].Pattern) != len(models[j].Pattern) {
+			return len(models[i].Pattern) > len(models[j].Pattern)
+		}
+		return models[i].Pattern < models[j].Pattern
+	})
+	return models
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ONUState represents the state of one ONU from show gpon onu state
+type ONUState struct {
+	OnuIndex   string `json:"onu_index"`
+	Rack       int    `json:"rack"`
+	Board      int    `json:"board"`
+	PON        int    `json:"pon"`
+	ONU        int    `json:"onu"`
+	AdminState string `json:"admin_state"`
+	OMCCState  string `json:"omcc_state"`
+	PhaseState string `json:"phase_state"`
+	Channel    string `json:"channel,omitempty"`
+}
+
+// ONUStateList represents the parsed state table of a PON port or OLT
+type ONUStateList struct {
+	Host       string         `json:"host"`
+	TotalCount int            `json:"total_count"`
+	ONUs       []ONUState     `json:"onus"`
+	ByPhase    map[string]int `json:"by_phase_state"`
+	RawOutput  string         `json:"raw_output,omitempty"`
+}
+
+// onuStateFSM parses the output of "show gpon onu state"
+var onuStateFSM = MustLoadTextFSM("zte_show_gpon_onu_state")
+
+// onuIndexRE splits an ONU index such as 1/2/1:5
+var onuIndexRE = regexp.MustCompile(`(\d+)/(\d+)/(\d+):(\d+)`)
+
+// ParseONUStateOutput parses the raw output from show gpon onu state command
+func ParseONUStateOutput(host string, rawOutput string) *ONUStateList {
+	data := &ONUStateList{
+		Host:      host,
+		ONUs:      []ONUState{},
+		ByPhase:   make(map[string]int),
+		RawOutput: rawOutput,
+	}
+
+	records, err := onuStateFSM.Parse(cleanOutput(rawOutput))
+	if err != nil {
+		return data
+	}
+
+	for _, rec := range records {
+		state := ONUState{
+			OnuIndex:   rec.String("OnuIndex"),
+			AdminState: rec.String("AdminState"),
+			OMCCState:  rec.String("OmccState"),
+			PhaseState: rec.String("PhaseState"),
+			Channel:    rec.String("Channel"),
+		}
+		state.Rack, state.Board, state.PON, state.ONU = ParseONUIndex(state.OnuIndex)
+
+		data.ONUs = append(data.ONUs, state)
+		data.ByPhase[strings.ToLower(state.PhaseState)]++
+	}
+	data.TotalCount = len(data.ONUs)
+
+	return data
+}
+
+// ParseONUIndex splits an ONU index like 1/2/1:5 or gpon-onu_1/2/1:5 into
+// rack, board, PON and ONU numbers
+func ParseONUIndex(index string) (rack, board, pon, onu int) {
+	m := onuIndexRE.FindStringSubmatch(index)
+	if m == nil {
+		return 0, 0, 0, 0
+	}
+
+	rack, _ = strconv.Atoi(m[1])
+	board, _ = strconv.Atoi(m[2])
+	pon, _ = strconv.Atoi(m[3])
+	onu, _ = strconv.Atoi(m[4])
+	return rack, board, pon, onu
+}
+
+// FilterONUStates returns the ONUs matching the given states; empty filters
+// match everything and comparisons ignore case
+func FilterONUStates(onus []ONUState, board int, phase, admin, omcc string) []ONUState {
+	filtered := []ONUState{}
+	for _, onu := range onus {
+		if board > 0 && onu.Board != board {
+			continue
+		}
+		if phase != "" && !strings.EqualFold(onu.PhaseState, phase) {
+			continue
+		}
+		if admin != "" && !strings.EqualFold(onu.AdminState, admin) {
+			continue
+		}
+		if omcc != "" && !strings.EqualFold(onu.OMCCState, omcc) {
+			continue
+		}
+		filtered = append(filtered, onu)
+	}
+	return filtered
+}
@@ -9,10 +9,21 @@ import (
 // UnconfiguredONU represents parsed ONU unconfigured data
 type UnconfiguredONU struct {
 	OLTIndex     string `json:"olt_index"`
+	Technology   string `json:"technology"` // gpon, xg-pon, xgs-pon, epon, 10g-epon
 	Model        string `json:"model"`
-	SerialNumber string `json:"serial_number"`
-	Board        int    `json:"board"`
-	PON          int    `json:"pon"`
+	SerialNumber string `json:"serial_number,omitempty"`
+	MACAddress   string `json:"mac_address,omitempty"` // EPON ONUs
+	Password     string `json:"password,omitempty"`
+	LOID         string `json:"loid,omitempty"`
+	LOIDPassword string `json:"loid_password,omitempty"`
+	State        string `json:"state,omitempty"`
+	Rack         int    `json:"rack"`
+	Shelf        int    `json:"shelf"`
+	Slot         int    `json:"slot"`
+	Port         int    `json:"port"`
+	ONU          int    `json:"onu,omitempty"` // set when the OLT lists ONU interfaces
+	Board        int    `json:"board"`         // same as slot
+	PON          int    `json:"pon"`           // same as port
 }
 
 // UnconfiguredONUList represents list of unconfigured ONUs
@@ -46,60 +57,112 @@ func ParseUnconfiguredONUOutput(host string, rawOutput string) *UnconfiguredONUL
 	return data
 }
 
+// unconfiguredFSM parses the output of "show pon onu uncfg"
+var unconfiguredFSM = MustLoadTextFSM("zte_show_pon_onu_uncfg")
+
 // parseONUEntries parses individual ONU entries from the output
 func parseONUEntries(output string) []UnconfiguredONU {
 	var onus []UnconfiguredONU
 
-	// Pattern to match ONU lines
-	// Example: "gpon-olt_1/1/14     F660V8.0                 RTEGC6A1BF4D"
-	pattern := `^(gpon-olt_\d+/\d+/\d+)\s+(\w+\S*)\s+(\w+)$`
-
-	re := regexp.MustCompile(pattern)
-	lines := strings.Split(output, "\n")
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.Contains(line, "OltIndex") || strings.Contains(line, "----") {
-			continue
-		}
-
-		matches := re.FindStringSubmatch(line)
-		if matches == nil {
-			continue
-		}
-
-		// Extract slot and port from OLT index
-		board, pon := parseOLTSlotPort(matches[1])
-
-		onu := UnconfiguredONU{
-			OLTIndex:     matches[1],
-			Model:        matches[2],
-			SerialNumber: matches[3],
-			Board:        board,
-			PON:          pon,
-		}
+	records, err := unconfiguredFSM.Parse(output)
+	if err != nil {
+		return onus
+	}
 
-		onus = append(onus, onu)
+	for _, rec := range records {
+		index := rec.String("Interface")
+		loc := ParseInterfaceLocation(index)
+
+		onus = append(onus, UnconfiguredONU{
+			OLTIndex:     index,
+			Technology:   loc.Technology,
+			Model:        placeholder(rec.String("Model")),
+			SerialNumber: rec.String("SerialNumber"),
+			MACAddress:   rec.String("MACAddress"),
+			Password:     placeholder(rec.String("Password")),
+			LOID:         placeholder(rec.String("LOID")),
+			LOIDPassword: placeholder(rec.String("LOIDPassword")),
+			State:        rec.String("State"),
+			Rack:         loc.Rack,
+			Shelf:        loc.Shelf,
+			Slot:         loc.Slot,
+			Port:         loc.Port,
+			ONU:          loc.ONU,
+			Board:        loc.Slot,
+			PON:          loc.Port,
+		})
 	}
 
 	return onus
 }
 
-// parseOLTSlotPort extracts slot and port from OLT index string
-func parseOLTSlotPort(oltIndex string) (int, int) {
-	// Pattern: gpon-olt_1/1/14
-	pattern := `gpon-olt_\d+/(\d+)/(\d+)`
-	re := regexp.MustCompile(pattern)
-	matches := re.FindStringSubmatch(oltIndex)
+// InterfaceLocation is the position of a PON or ONU interface in the chassis
+type InterfaceLocation struct {
+	Technology string `json:"technology"`
+	Rack       int    `json:"rack"`
+	Shelf      int    `json:"shelf"`
+	Slot       int    `json:"slot"`
+	Port       int    `json:"port"`
+	ONU        int    `json:"onu,omitempty"`
+}
 
-	if len(matches) < 3 {
-		return 0, 0
+// interfaceRE matches C300 (gpon-olt_1/2/3), C600 (gpon_olt-1/2/3) and other
+// PON technology interface names, with an optional rack and ONU number
+var interfaceRE = regexp.MustCompile(`(?i)^([a-z0-9-]+)[-_](?:olt|onu)[-_](\d+(?:/\d+)+)(?::(\d+))?$`)
+
+// ParseInterfaceLocation extracts technology, rack, shelf, slot, port and ONU
+// from an interface name. Three numbers are shelf/slot/port on rack 1, four
+// are rack/shelf/slot/port.
+func ParseInterfaceLocation(name string) InterfaceLocation {
+	m := interfaceRE.FindStringSubmatch(strings.TrimSpace(name))
+	if m == nil {
+		return InterfaceLocation{}
 	}
 
-	slot, _ := strconv.Atoi(matches[1])
-	port, _ := strconv.Atoi(matches[2])
+	loc := InterfaceLocation{Technology: ponTechnology(m[1]), Rack: 1}
+	var nums []int
+	for _, part := range strings.Split(m[2], "/") {
+		n, _ := strconv.Atoi(part)
+		nums = append(nums, n)
+	}
+	switch len(nums) {
+	case 3:
+		loc.Shelf, loc.Slot, loc.Port = nums[0], nums[1], nums[2]
+	case 4:
+		loc.Rack, loc.Shelf, loc.Slot, loc.Port = nums[0], nums[1], nums[2], nums[3]
+	default:
+		return InterfaceLocation{Technology: loc.Technology}
+	}
+	if m[3] != "" {
+		loc.ONU, _ = strconv.Atoi(m[3])
+	}
+	return loc
+}
 
-	return slot, port
+// ponTechnology normalizes an interface prefix such as gpon, xgs, xgspon or epon
+func ponTechnology(prefix string) string {
+	p := strings.ToLower(prefix)
+	switch {
+	case strings.Contains(p, "10g") && strings.Contains(p, "epon"):
+		return "10g-epon"
+	case strings.Contains(p, "epon"):
+		return "epon"
+	case strings.HasPrefix(p, "xgs"):
+		return "xgs-pon"
+	case strings.HasPrefix(p, "xg"):
+		return "xg-pon"
+	default:
+		return "gpon"
+	}
+}
+
+// placeholder turns the N/A and dash placeholders printed for empty columns into ""
+func placeholder(v string) string {
+	switch strings.ToUpper(v) {
+	case "N/A", "NA", "-", "--":
+		return ""
+	}
+	return v
 }
 
 // groupONUsBySlot groups ONUs by their slot
@@ -138,28 +201,10 @@ func cleanONUOutput(output string) string {
 
 // GetONUModelDescription returns human readable model description
 func GetONUModelDescription(model string) string {
-	modelUpper := strings.ToUpper(model)
-
-	switch {
-	case strings.Contains(modelUpper, "F660"):
-		return "ZTE F660 Series GPON ONU"
-	case strings.Contains(modelUpper, "F620"):
-		return "ZTE F620 Series GPON ONU"
-	case strings.Contains(modelUpper, "F660V8"):
-		return "ZTE F660V8 GPON ONU (Latest)"
-	case strings.Contains(modelUpper, "F660V5"):
-		return "ZTE F660V5 GPON ONU (Mid-gen)"
-	case strings.Contains(modelUpper, "F660V3"):
-		return "ZTE F660V3 GPON ONU (Older)"
-	case strings.Contains(modelUpper, "F601"):
-		return "ZTE F601 GPON ONU"
-	case strings.Contains(modelUpper, "AN5506"):
-		return "Fiberhome AN5506 Series ONU"
-	case strings.Contains(modelUpper, "HG8245"):
-		return "Huawei HG8245 Series ONU"
-	default:
-		return "Unknown ONU Model: " + model
+	if m, ok := LookupONUModel(model); ok {
+		return m.Description
 	}
+	return "Unknown ONU Model: " + model
 }
 
 // GetUnconfiguredStatus returns status based on count
@@ -0,0 +1,124 @@
+package utils
+
+import "fmt"
+
+// AttenuationThresholds holds the upper bounds in dB of each attenuation status;
+// anything above Warning is critical
+type AttenuationThresholds struct {
+	Excellent float64 `json:"excellent"`
+	Good      float64 `json:"good"`
+	Normal    float64 `json:"normal"`
+	Warning   float64 `json:"warning"`
+}
+
+// RxPowerThresholds holds the absolute rx power bands in dBm
+type RxPowerThresholds struct {
+	Overload float64 `json:"overload"` // above: receiver overload
+	Warning  float64 `json:"warning"`  // below: warning
+	Critical float64 `json:"critical"` // below: critical
+}
+
+// RangeThresholds holds the bands of a reading: values outside
+// WarningLow..WarningHigh are warning, outside CriticalLow..CriticalHigh critical
+type RangeThresholds struct {
+	CriticalLow  float64 `json:"critical_low"`
+	WarningLow   float64 `json:"warning_low"`
+	WarningHigh  float64 `json:"warning_high"`
+	CriticalHigh float64 `json:"critical_high"`
+}
+
+// SFPThresholds holds the bands of the PON port optical module readings
+type SFPThresholds struct {
+	TxPower     RangeThresholds `json:"tx_power"`     // dBm
+	Temperature RangeThresholds `json:"temperature"`  // °C
+	BiasCurrent RangeThresholds `json:"bias_current"` // mA
+	Voltage     RangeThresholds `json:"voltage"`      // V
+}
+
+// OpticalThresholds is a threshold profile used to classify optical levels
+type OpticalThresholds struct {
+	Attenuation AttenuationThresholds `json:"attenuation"`
+	RxPower     RxPowerThresholds     `json:"rx_power"`
+	SFP         SFPThresholds         `json:"sfp"`
+}
+
+// DefaultOpticalThresholds suits class B+ optics on a standard reach PON
+var DefaultOpticalThresholds = OpticalThresholds{
+	Attenuation: AttenuationThresholds{Excellent: 10, Good: 15, Normal: 25, Warning: 30},
+	RxPower:     RxPowerThresholds{Overload: -8, Warning: -25, Critical: -27},
+	SFP: SFPThresholds{
+		TxPower:     RangeThresholds{CriticalLow: 0.5, WarningLow: 1.5, WarningHigh: 7, CriticalHigh: 8},
+		Temperature: RangeThresholds{CriticalLow: -5, WarningLow: 0, WarningHigh: 70, CriticalHigh: 80},
+		BiasCurrent: RangeThresholds{CriticalLow: 1, WarningLow: 2, WarningHigh: 80, CriticalHigh: 100},
+		Voltage:     RangeThresholds{CriticalLow: 2.97, WarningLow: 3.13, WarningHigh: 3.47, CriticalHigh: 3.63},
+	},
+}
+
+// Validate checks that the bands are in ascending order
+func (t OpticalThresholds) Validate() error {
+	a := t.Attenuation
+	if !(a.Excellent < a.Good && a.Good < a.Normal && a.Normal < a.Warning) {
+		return fmt.Errorf("attenuation thresholds must satisfy excellent < good < normal < warning")
+	}
+	r := t.RxPower
+	if !(r.Critical < r.Warning && r.Warning < r.Overload) {
+		return fmt.Errorf("rx_power thresholds must satisfy critical < warning < overload")
+	}
+	for name, b := range map[string]RangeThresholds{
+		"tx_power":     t.SFP.TxPower,
+		"temperature":  t.SFP.Temperature,
+		"bias_current": t.SFP.BiasCurrent,
+		"voltage":      t.SFP.Voltage,
+	} {
+		if !(b.CriticalLow < b.WarningLow && b.WarningLow < b.WarningHigh && b.WarningHigh < b.CriticalHigh) {
+			return fmt.Errorf("sfp %s thresholds must satisfy critical_low < warning_low < warning_high < critical_high", name)
+		}
+	}
+	return nil
+}
+
+// AttenuationStatus classifies an attenuation value in dB
+func (t OpticalThresholds) AttenuationStatus(attenuation float64) string {
+	a := t.Attenuation
+	switch {
+	case attenuation < 0:
+		return "error"
+	case attenuation <= a.Excellent:
+		return "excellent"
+	case attenuation <= a.Good:
+		return "good"
+	case attenuation <= a.Normal:
+		return "normal"
+	case attenuation <= a.Warning:
+		return "warning"
+	default:
+		return "critical"
+	}
+}
+
+// RxPowerStatus classifies a received optical power in dBm
+func (t OpticalThresholds) RxPowerStatus(rx float64) string {
+	r := t.RxPower
+	switch {
+	case rx > r.Overload:
+		return "overload"
+	case rx < r.Critical:
+		return "critical"
+	case rx < r.Warning:
+		return "warning"
+	default:
+		return "normal"
+	}
+}
+
+// Status classifies a reading as normal, warning or critical
+func (b RangeThresholds) Status(value float64) string {
+	switch {
+	case value < b.CriticalLow || value > b.CriticalHigh:
+		return "critical"
+	case value < b.WarningLow || value > b.WarningHigh:
+		return "warning"
+	default:
+		return "normal"
+	}
+}
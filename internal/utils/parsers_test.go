@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestParsersGolden runs every parser over its CLI output in testdata and
+// compares the result with the matching .golden.json file
+func TestParsersGolden(t *testing.T) {
+	tests := []struct {
+		fixture string
+		parse   func(raw string) any
+	}{
+		{"attenuation_c300", func(raw string) any {
+			d := ParseAttenuationOutput("olt", 2, 1, 1, raw)
+			d.RawOutput = ""
+			return d
+		}},
+		{"attenuation_c600", func(raw string) any {
+			d := ParseAttenuationOutput("olt", 1, 3, 12, raw)
+			d.RawOutput = ""
+			return d
+		}},
+		{"uncfg_c300", parseUncfg},
+		{"uncfg_c300_gpon", parseUncfg},
+		{"uncfg_c600", parseUncfg},
+		{"uncfg_xgs", parseUncfg},
+		{"uncfg_epon", parseUncfg},
+		{"onu_state_c300", parseState},
+		{"onu_state_c600", parseState},
+		{"detail_info_c300", parseDetail},
+		{"detail_info_c600", parseDetail},
+		{"pon_power_c300", func(raw string) any {
+			d := ParsePONPowerOutput("olt", 2, 1, 7.124, raw)
+			d.RawOutput = ""
+			return d
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			raw, err := os.ReadFile(filepath.Join("testdata", tt.fixture+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.MarshalIndent(tt.parse(string(raw)), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", tt.fixture+".golden.json")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("result differs from %s (run with -update to rewrite it)\ngot:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func parseUncfg(raw string) any {
+	d := ParseUnconfiguredONUOutput("olt", raw)
+	d.RawOutput = ""
+	return d
+}
+
+func parseState(raw string) any {
+	d := ParseONUStateOutput("olt", raw)
+	d.RawOutput = ""
+	return d
+}
+
+func parseDetail(raw string) any {
+	d := ParseONUDetailOutput("olt", raw)
+	d.RawOutput = ""
+	return d
+}
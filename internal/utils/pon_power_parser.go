@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// DefaultOLTTxPower is the assumed OLT transmit power in dBm used to estimate
+// downstream attenuation when the caller does not supply it
+const DefaultOLTTxPower = 5.0
+
+// PONPowerEntry represents the optical levels of one ONU on a PON port
+type PONPowerEntry struct {
+	Interface   string   `json:"interface"`
+	ONU         int      `json:"onu"`
+	ONURxPower  *float64 `json:"onu_rx_power_dbm"` // nil when the ONU reports N/A
+	OLTRxPower  *float64 `json:"olt_rx_power_dbm"`
+	Attenuation *float64 `json:"attenuation_db"` // OLT Tx minus ONU Rx
+	Status      string   `json:"status"`
+	RxStatus    string   `json:"rx_status"` // ONU rx power band
+}
+
+// PONPowerSummary summarizes the optical levels of a PON port
+type PONPowerSummary struct {
+	Histogram      map[string]int `json:"histogram"`
+	RxHistogram    map[string]int `json:"rx_histogram"`
+	MinONURxPower  *float64       `json:"min_onu_rx_power_dbm,omitempty"`
+	MaxONURxPower  *float64       `json:"max_onu_rx_power_dbm,omitempty"`
+	AvgONURxPower  *float64       `json:"avg_onu_rx_power_dbm,omitempty"`
+	AvgAttenuation *float64       `json:"avg_attenuation_db,omitempty"`
+}
+
+// PONPowerSweep represents the optical levels of every ONU on a PON port
+type PONPowerSweep struct {
+	Host       string          `json:"host"`
+	Board      int             `json:"board"`
+	PON        int             `json:"pon"`
+	OLTTxPower float64         `json:"olt_tx_power_dbm"`
+	TotalCount int             `json:"total_count"`
+	ONUs       []PONPowerEntry `json:"onus"`
+	Summary    PONPowerSummary `json:"summary"`
+	RawOutput  string          `json:"raw_output,omitempty"`
+}
+
+// powerStatuses and rxStatuses list every status reported in the histograms
+var (
+	powerStatuses = []string{"excellent", "good", "normal", "warning", "critical", "error", "unknown"}
+	rxStatuses    = []string{"overload", "normal", "warning", "critical", "unknown"}
+)
+
+// ponPowerFSM parses the output of "show pon power onu-rx/olt-rx"
+var ponPowerFSM = MustLoadTextFSM("zte_show_pon_power_rx")
+
+// ParsePONPowerOutput parses the combined output of show pon power onu-rx and
+// olt-rx for a PON port and classifies each ONU by its downstream attenuation
+func ParsePONPowerOutput(host string, board, pon int, oltTxPower float64, rawOutput string) *PONPowerSweep {
+	data := &PONPowerSweep{
+		Host:       host,
+		Board:      board,
+		PON:        pon,
+		OLTTxPower: oltTxPower,
+		ONUs:       []PONPowerEntry{},
+		RawOutput:  rawOutput,
+	}
+
+	records, err := ponPowerFSM.Parse(cleanOutput(rawOutput))
+	if err != nil {
+		records = nil
+	}
+
+	// Merge onu-rx and olt-rx rows of the same ONU
+	byIndex := make(map[string]*PONPowerEntry)
+	var order []string
+	for _, rec := range records {
+		index := rec.String("Interface")
+		entry, ok := byIndex[index]
+		if !ok {
+			_, _, _, onu := ParseONUIndex(index)
+			entry = &PONPowerEntry{Interface: index, ONU: onu}
+			byIndex[index] = entry
+			order = append(order, index)
+		}
+
+		power, ok := rec.Float("Power")
+		if !ok {
+			continue // N/A, the ONU is offline
+		}
+		if rec.String("Side") == "olt-rx" {
+			entry.OLTRxPower = &power
+		} else {
+			entry.ONURxPower = &power
+		}
+	}
+
+	for _, index := range order {
+		entry := byIndex[index]
+		if entry.ONURxPower != nil {
+			att := round3(oltTxPower - *entry.ONURxPower)
+			entry.Attenuation = &att
+		}
+		data.ONUs = append(data.ONUs, *entry)
+	}
+
+	data.TotalCount = len(data.ONUs)
+	data.Classify(DefaultOpticalThresholds)
+	_ = SortPONPower(data.ONUs, "attenuation")
+
+	return data
+}
+
+// Classify sets the status of every ONU using a threshold profile and rebuilds the summary
+func (s *PONPowerSweep) Classify(t OpticalThresholds) {
+	for i := range s.ONUs {
+		e := &s.ONUs[i]
+		e.Status, e.RxStatus = "unknown", "unknown"
+		if e.Attenuation != nil {
+			e.Status = t.AttenuationStatus(*e.Attenuation)
+		}
+		if e.ONURxPower != nil {
+			e.RxStatus = t.RxPowerStatus(*e.ONURxPower)
+		}
+	}
+	s.Summary = summarizePONPower(s.ONUs)
+}
+
+// SortPONPower sorts entries in place: by attenuation and OLT rx worst first,
+// by ONU rx weakest first or by ONU index; entries without a value go last
+func SortPONPower(entries []PONPowerEntry, by string) error {
+	var less func(a, b PONPowerEntry) bool
+	switch by {
+	case "", "attenuation":
+		less = func(a, b PONPowerEntry) bool { return greater(a.Attenuation, b.Attenuation, a.ONU < b.ONU) }
+	case "onu_rx":
+		less = func(a, b PONPowerEntry) bool { return lower(a.ONURxPower, b.ONURxPower, a.ONU < b.ONU) }
+	case "olt_rx":
+		less = func(a, b PONPowerEntry) bool { return lower(a.OLTRxPower, b.OLTRxPower, a.ONU < b.ONU) }
+	case "onu":
+		less = func(a, b PONPowerEntry) bool { return a.ONU < b.ONU }
+	default:
+		return fmt.Errorf("invalid sort key %q (supported: attenuation, onu, onu_rx, olt_rx)", by)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return less(entries[i], entries[j]) })
+	return nil
+}
+
+// summarizePONPower builds the status histogram and rx power statistics
+func summarizePONPower(entries []PONPowerEntry) PONPowerSummary {
+	summary := PONPowerSummary{
+		Histogram:   make(map[string]int, len(powerStatuses)),
+		RxHistogram: make(map[string]int, len(rxStatuses)),
+	}
+	for _, status := range powerStatuses {
+		summary.Histogram[status] = 0
+	}
+	for _, status := range rxStatuses {
+		summary.RxHistogram[status] = 0
+	}
+
+	var rxSum, attSum float64
+	var rxCount int
+	minRx, maxRx := math.Inf(1), math.Inf(-1)
+	for _, e := range entries {
+		summary.Histogram[e.Status]++
+		summary.RxHistogram[e.RxStatus]++
+		if e.ONURxPower == nil {
+			continue
+		}
+		rx := *e.ONURxPower
+		rxSum += rx
+		attSum += *e.Attenuation
+		rxCount++
+		minRx = math.Min(minRx, rx)
+		maxRx = math.Max(maxRx, rx)
+	}
+
+	if rxCount > 0 {
+		avgRx := round3(rxSum / float64(rxCount))
+		avgAtt := round3(attSum / float64(rxCount))
+		summary.MinONURxPower = &minRx
+		summary.MaxONURxPower = &maxRx
+		summary.AvgONURxPower = &avgRx
+		summary.AvgAttenuation = &avgAtt
+	}
+	return summary
+}
+
+// greater orders present values descending, falling back to tie when equal
+func greater(a, b *float64, tie bool) bool {
+	switch {
+	case a == nil || b == nil:
+		return a != nil || (b == nil && tie)
+	case *a != *b:
+		return *a > *b
+	}
+	return tie
+}
+
+// lower orders present values ascending, falling back to tie when equal
+func lower(a, b *float64, tie bool) bool {
+	switch {
+	case a == nil || b == nil:
+		return a != nil || (b == nil && tie)
+	case *a != *b:
+		return *a < *b
+	}
+	return tie
+}
+
+// round3 rounds a dB value to three decimals as printed by the OLT
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
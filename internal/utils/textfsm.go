@@ -0,0 +1,513 @@
+package utils
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// textfsmFS holds the built-in TextFSM templates for CLI output
+//
+//go:embed textfsm/*.textfsm
+var textfsmFS embed.FS
+
+// Value options supported in TextFSM templates. Key, which only marks values
+// identifying a record for table tools, is rejected rather than ignored.
+const (
+	fsmFilldown = "Filldown"
+	fsmKey      = "Key"
+	fsmRequired = "Required"
+	fsmList     = "List"
+	fsmFillup   = "Fillup"
+)
+
+// Reserved TextFSM state names
+const (
+	fsmStart = "Start"
+	fsmEOF   = "EOF"
+	fsmEnd   = "End"
+)
+
+var (
+	fsmValueNameRE = regexp.MustCompile(`^\w+$`)
+	fsmVarRE       = regexp.MustCompile(`\$\{(\w+)\}|\$(\w+)`)
+
+	fsmCache sync.Map // template name -> *TextFSM
+)
+
+// fsmValue is a Value definition of a TextFSM template
+type fsmValue struct {
+	name    string
+	regex   string
+	options map[string]bool
+}
+
+// fsmRule is a single rule of a TextFSM state
+type fsmRule struct {
+	line     string
+	re       *regexp.Regexp
+	lineOp   string // Next or Continue
+	recordOp string // NoRecord, Record, Clear or Clearall
+	newState string
+	err      bool
+	errMsg   string
+}
+
+// TextFSM is a parsed, TextFSM-compatible template: value definitions plus a
+// state machine of regex rules that turns CLI output into records
+type TextFSM struct {
+	values []*fsmValue
+	states map[string][]fsmRule
+}
+
+// Record is a single row extracted by a TextFSM template. Values are strings,
+// or []string for List values.
+type Record map[string]any
+
+// String returns a value as a string
+func (r Record) String(name string) string {
+	switch v := r[name].(type) {
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, " ")
+	}
+	return ""
+}
+
+// Int returns a value as an int, or 0 if it is empty or not a number
+func (r Record) Int(name string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(r.String(name)))
+	return n
+}
+
+// Float returns a value as a float64 and whether it could be parsed
+func (r Record) Float(name string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(r.String(name)), 64)
+	return f, err == nil
+}
+
+// List returns a List value
+func (r Record) List(name string) []string {
+	switch v := r[name].(type) {
+	case []string:
+		return v
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	}
+	return nil
+}
+
+// LoadTextFSM returns a built-in template from internal/utils/textfsm by name
+// (without the .textfsm extension); parsed templates are cached
+func LoadTextFSM(name string) (*TextFSM, error) {
+	if t, ok := fsmCache.Load(name); ok {
+		return t.(*TextFSM), nil
+	}
+
+	src, err := textfsmFS.ReadFile("textfsm/" + name + ".textfsm")
+	if err != nil {
+		return nil, fmt.Errorf("textfsm template %s not found", name)
+	}
+
+	t, err := ParseTextFSM(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("textfsm template %s: %w", name, err)
+	}
+
+	fsmCache.Store(name, t)
+	return t, nil
+}
+
+// MustLoadTextFSM is like LoadTextFSM but panics if the template is missing or invalid
+func MustLoadTextFSM(name string) *TextFSM {
+	t, err := LoadTextFSM(name)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// ParseTextFSM parses a template in TextFSM syntax
+func ParseTextFSM(src string) (*TextFSM, error) {
+	t := &TextFSM{states: make(map[string][]fsmRule)}
+
+	scanner := bufio.NewScanner(strings.NewReader(strings.ReplaceAll(src, "\r\n", "\n")))
+	lineNo := 0
+	inValues := true
+	state := ""
+
+	for scanner.Scan() {
+		lineNo++
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if inValues {
+			if line == "" {
+				if len(t.values) > 0 {
+					inValues = false
+				}
+				continue
+			}
+			if !strings.HasPrefix(line, "Value ") {
+				return nil, fmt.Errorf("line %d: expected Value definition", lineNo)
+			}
+			v, err := parseFSMValue(line[len("Value "):])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			if t.value(v.name) != nil {
+				return nil, fmt.Errorf("line %d: duplicate value %s", lineNo, v.name)
+			}
+			t.values = append(t.values, v)
+			continue
+		}
+
+		if line == "" {
+			state = ""
+			continue
+		}
+
+		if state == "" {
+			if raw != line || !fsmValueNameRE.MatchString(line) {
+				return nil, fmt.Errorf("line %d: expected state name", lineNo)
+			}
+			if _, dup := t.states[line]; dup {
+				return nil, fmt.Errorf("line %d: duplicate state %s", lineNo, line)
+			}
+			if line == fsmEnd {
+				return nil, fmt.Errorf("line %d: state End is reserved", lineNo)
+			}
+			state = line
+			t.states[state] = nil
+			continue
+		}
+
+		if !strings.HasPrefix(line, "^") || raw == line {
+			return nil, fmt.Errorf("line %d: rules must be indented and start with ^", lineNo)
+		}
+		rule, err := t.parseRule(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		t.states[state] = append(t.states[state], rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return t, t.validate()
+}
+
+// parseFSMValue parses "[Options] Name (regex)"
+func parseFSMValue(def string) (*fsmValue, error) {
+	first, rest, _ := strings.Cut(strings.TrimSpace(def), " ")
+	rest = strings.TrimSpace(rest)
+
+	v := &fsmValue{options: make(map[string]bool)}
+	if strings.HasPrefix(rest, "(") {
+		v.name = first
+	} else {
+		for _, opt := range strings.Split(first, ",") {
+			switch opt {
+			case fsmFilldown, fsmRequired, fsmList, fsmFillup:
+				v.options[opt] = true
+			case fsmKey:
+				return nil, fmt.Errorf("value option %s is not supported", opt)
+			default:
+				return nil, fmt.Errorf("unknown value option %q", opt)
+			}
+		}
+		v.name, rest, _ = strings.Cut(rest, " ")
+		rest = strings.TrimSpace(rest)
+	}
+
+	if !fsmValueNameRE.MatchString(v.name) {
+		return nil, fmt.Errorf("invalid value name %q", v.name)
+	}
+	if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+		return nil, fmt.Errorf("value %s: regex must be enclosed in parentheses", v.name)
+	}
+	if _, err := regexp.Compile(rest); err != nil {
+		return nil, fmt.Errorf("value %s: %w", v.name, err)
+	}
+
+	v.regex = rest
+	return v, nil
+}
+
+// parseRule parses "^regex [-> action]", substituting ${Value} references
+func (t *TextFSM) parseRule(line string) (fsmRule, error) {
+	rule := fsmRule{line: line, lineOp: "Next", recordOp: "NoRecord"}
+
+	pattern, action := line, ""
+	if i := strings.LastIndex(line, " -> "); i >= 0 {
+		pattern, action = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+4:])
+	}
+
+	// $$ is a literal end-of-line anchor; ${Name} and $Name refer to values
+	const anchor = "\x00"
+	pattern = strings.ReplaceAll(pattern, "$$", anchor)
+	var subErr error
+	pattern = fsmVarRE.ReplaceAllStringFunc(pattern, func(m string) string {
+		name := strings.Trim(m, "${}")
+		v := t.value(name)
+		if v == nil {
+			subErr = fmt.Errorf("unknown value %s", name)
+			return m
+		}
+		return "(?P<" + name + ">" + v.regex[1:]
+	})
+	if subErr != nil {
+		return rule, subErr
+	}
+	pattern = strings.ReplaceAll(pattern, anchor, "$")
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return rule, err
+	}
+	rule.re = re
+
+	if action == "" {
+		return rule, nil
+	}
+
+	if strings.HasPrefix(action, "Error") {
+		rule.err = true
+		rule.errMsg = strings.Trim(strings.TrimSpace(strings.TrimPrefix(action, "Error")), `"`)
+		return rule, nil
+	}
+
+	fields := strings.Fields(action)
+	if len(fields) > 2 {
+		return rule, fmt.Errorf("invalid action %q", action)
+	}
+
+	op := fields[0]
+	switch {
+	case strings.Contains(op, "."):
+		rule.lineOp, rule.recordOp, _ = strings.Cut(op, ".")
+	case isFSMLineOp(op):
+		rule.lineOp = op
+	case isFSMRecordOp(op):
+		rule.recordOp = op
+	default:
+		if len(fields) > 1 {
+			return rule, fmt.Errorf("invalid action %q", action)
+		}
+		rule.newState = op
+		return rule, nil
+	}
+	if len(fields) == 2 {
+		rule.newState = fields[1]
+	}
+
+	if !isFSMLineOp(rule.lineOp) || !isFSMRecordOp(rule.recordOp) {
+		return rule, fmt.Errorf("invalid action %q", action)
+	}
+	if rule.lineOp == "Continue" && rule.newState != "" {
+		return rule, fmt.Errorf("cannot change state on Continue")
+	}
+	return rule, nil
+}
+
+// validate checks that the template has a Start state and known transitions
+func (t *TextFSM) validate() error {
+	if len(t.values) == 0 {
+		return fmt.Errorf("no Value definitions")
+	}
+	if _, ok := t.states[fsmStart]; !ok {
+		return fmt.Errorf("missing Start state")
+	}
+	for name, rules := range t.states {
+		for _, r := range rules {
+			if r.newState == "" || r.newState == fsmEnd {
+				continue
+			}
+			if _, ok := t.states[r.newState]; !ok {
+				return fmt.Errorf("state %s: transition to undefined state %s", name, r.newState)
+			}
+		}
+	}
+	return nil
+}
+
+// value returns the value definition with the given name
+func (t *TextFSM) value(name string) *fsmValue {
+	for _, v := range t.values {
+		if v.name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// Header returns the value names in definition order
+func (t *TextFSM) Header() []string {
+	names := make([]string, len(t.values))
+	for i, v := range t.values {
+		names[i] = v.name
+	}
+	return names
+}
+
+// Parse runs the state machine over text and returns the extracted records
+func (t *TextFSM) Parse(text string) ([]Record, error) {
+	p := &fsmParser{t: t, cur: make(map[string]any)}
+	state := fsmStart
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for lineNo, line := range lines {
+		line = strings.TrimRight(line, "\r")
+
+	rules:
+		for _, r := range t.states[state] {
+			m := r.re.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+
+			for i, name := range r.re.SubexpNames() {
+				if i > 0 && name != "" && t.value(name) != nil && m[i] != "" {
+					p.assign(name, m[i])
+				}
+			}
+
+			if r.err {
+				msg := r.errMsg
+				if msg == "" {
+					msg = "rule " + r.line
+				}
+				return nil, fmt.Errorf("textfsm error on line %d (%q): %s", lineNo+1, line, msg)
+			}
+
+			switch r.recordOp {
+			case "Record":
+				p.record()
+			case "Clear":
+				p.clear(false)
+			case "Clearall":
+				p.clear(true)
+			}
+
+			if r.newState != "" {
+				state = r.newState
+			}
+			if state == fsmEnd {
+				return p.records, nil
+			}
+			if r.lineOp == "Next" {
+				break rules
+			}
+		}
+	}
+
+	// Without an explicit EOF state the last record is saved implicitly
+	if _, ok := t.states[fsmEOF]; !ok {
+		p.record()
+	}
+	return p.records, nil
+}
+
+// fsmParser holds the state of a single Parse run
+type fsmParser struct {
+	t       *TextFSM
+	cur     map[string]any
+	records []Record
+}
+
+// assign sets a value, appending to List values and filling up earlier records for Fillup values
+func (p *fsmParser) assign(name, value string) {
+	v := p.t.value(name)
+	if v.options[fsmList] {
+		list, _ := p.cur[name].([]string)
+		p.cur[name] = append(list, value)
+		return
+	}
+
+	p.cur[name] = value
+	if v.options[fsmFillup] {
+		for i := len(p.records) - 1; i >= 0; i-- {
+			if p.records[i].String(name) != "" {
+				break
+			}
+			p.records[i][name] = value
+		}
+	}
+}
+
+// record appends the current values as a record unless a Required value is
+// empty or no value is set, then clears the non-Filldown values
+func (p *fsmParser) record() {
+	rec := make(Record, len(p.t.values))
+	empty := true
+	for _, v := range p.t.values {
+		val, set := p.cur[v.name]
+		if v.options[fsmRequired] && (!set || isEmptyFSMValue(val)) {
+			p.clear(false)
+			return
+		}
+		if set && !isEmptyFSMValue(val) {
+			empty = false
+		}
+		switch {
+		case set:
+			rec[v.name] = val
+		case v.options[fsmList]:
+			rec[v.name] = []string{}
+		default:
+			rec[v.name] = ""
+		}
+	}
+
+	if empty {
+		return
+	}
+	p.records = append(p.records, rec)
+	p.clear(false)
+}
+
+// clear resets the current values; Filldown values are kept unless all is set
+func (p *fsmParser) clear(all bool) {
+	for _, v := range p.t.values {
+		if all || !v.options[fsmFilldown] {
+			delete(p.cur, v.name)
+		}
+	}
+}
+
+// isEmptyFSMValue reports whether a value holds no data
+func isEmptyFSMValue(v any) bool {
+	switch val := v.(type) {
+	case string:
+		return val == ""
+	case []string:
+		return len(val) == 0
+	}
+	return v == nil
+}
+
+// isFSMLineOp reports whether op is a TextFSM line action
+func isFSMLineOp(op string) bool {
+	return op == "Next" || op == "Continue"
+}
+
+// isFSMRecordOp reports whether op is a TextFSM record action
+func isFSMRecordOp(op string) bool {
+	switch op {
+	case "NoRecord", "Record", "Clear", "Clearall":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTextFSMErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{"key option", "Value Key Port (\\S+)\n\nStart\n  ^${Port} -> Record\n", "value option Key is not supported"},
+		{"unknown option", "Value Sticky Port (\\S+)\n\nStart\n  ^${Port} -> Record\n", `unknown value option "Sticky"`},
+		{"continue with state", "Value Port (\\S+)\n\nStart\n  ^${Port} -> Continue.Record Other\n\nOther\n  ^x\n", "cannot change state on Continue"},
+		{"no start state", "Value Port (\\S+)\n\nOther\n  ^${Port} -> Record\n", "Start"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseTextFSM(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want it to contain %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTextFSMParse(t *testing.T) {
+	src := `Value Filldown Board (\d+)
+Value Required Onu (\d+)
+Value List Vlan (\d+)
+Value Fillup Profile (\S+)
+
+Start
+  ^board ${Board}
+  ^onu ${Onu} -> Continue
+  ^onu \d+ vlan ${Vlan} -> Continue
+  ^.*vlan \d+ vlan ${Vlan}
+  ^profile ${Profile}
+  ^end -> Record
+  ^skip -> Clear
+`
+	fsm, err := ParseTextFSM(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := fsm.Parse(`board 2
+onu 1 vlan 100 vlan 200
+end
+onu 2 vlan 300
+skip
+vlan 400
+end
+onu 3
+profile gold
+end
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Record{
+		{"Board": "2", "Onu": "1", "Vlan": []string{"100", "200"}, "Profile": "gold"},
+		{"Board": "2", "Onu": "3", "Vlan": []string{}, "Profile": "gold"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("Parse() = %v, want %v", records, want)
+	}
+}
+
+func TestTextFSMErrorAction(t *testing.T) {
+	fsm, err := ParseTextFSM("Value Port (\\S+)\n\nStart\n  ^port ${Port} -> Record\n  ^% -> Error \"command failed\"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = fsm.Parse("port 1\n% Invalid input\n")
+	if err == nil || !strings.Contains(err.Error(), "command failed") {
+		t.Errorf("Parse() error = %v, want command failed", err)
+	}
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	entries, err := textfsmFS.ReadDir("textfsm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".textfsm")
+		if _, err := LoadTextFSM(name); err != nil {
+			t.Errorf("LoadTextFSM(%s): %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+// Package templates embeds the default command templates into the binary so the
+// server can run without a templates directory on disk.
+package templates
+
+import "embed"
+
+// FS holds the built-in command templates and service profiles
+//
+//go:embed *.tmpl c600/*.tmpl profiles/*.json
+var FS embed.FS